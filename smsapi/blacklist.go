@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"iter"
 	"strings"
 )
 
@@ -46,58 +47,85 @@ func (b *BlacklistPhoneNumbersCollectionIterator) Next() (*BlacklistPhoneNumberC
 	return bp, nil
 }
 
-func (blacklistApi *BlacklistApi) GetPhoneNumbers(ctx context.Context, filters *BlacklistPhoneNumbersCollectionFilters) (*BlacklistPhoneNumberCollection, error) {
+func (blacklistApi *BlacklistApi) GetPhoneNumbers(ctx context.Context, filters *BlacklistPhoneNumbersCollectionFilters, opts ...CallOption) (*BlacklistPhoneNumberCollection, error) {
+	ctx = withOperation(ctx, "BlacklistApi.GetPhoneNumbers")
+
 	var result = new(BlacklistPhoneNumberCollection)
 
 	uri, _ := addQueryParams(blacklistApiPath, filters)
 
-	err := blacklistApi.client.Get(ctx, uri, result)
+	err := blacklistApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
-func (blacklistApi *BlacklistApi) GetPageIterator(ctx context.Context, filters *BlacklistPhoneNumbersCollectionFilters) *BlacklistPhoneNumbersCollectionIterator {
-	ci := NewPageIterator(blacklistApi.client, ctx, blacklistApiPath, filters)
+func (blacklistApi *BlacklistApi) GetPageIterator(ctx context.Context, filters *BlacklistPhoneNumbersCollectionFilters, opts ...CallOption) *BlacklistPhoneNumbersCollectionIterator {
+	ctx = withOperation(ctx, "BlacklistApi.GetPageIterator")
+
+	ci := NewPageIterator(blacklistApi.client, ctx, blacklistApiPath, filters, opts...)
 	bi := &BlacklistPhoneNumbersCollectionIterator{ci}
 
 	return bi
 }
 
-func (blacklistApi *BlacklistApi) AddPhoneNumber(ctx context.Context, phoneNumber string, expireAt *Date) (*BlackListPhoneNumber, error) {
+// All iterates over blacklisted phone numbers matching the filters, fetching further pages on demand.
+func (blacklistApi *BlacklistApi) All(ctx context.Context, filters *BlacklistPhoneNumbersCollectionFilters, opts ...CallOption) iter.Seq2[*BlackListPhoneNumber, error] {
+	ctx = withOperation(ctx, "BlacklistApi.All")
+
+	return Paginate[BlackListPhoneNumber](ctx, blacklistApi.client, blacklistApiPath, filters, opts...)
+}
+
+func (blacklistApi *BlacklistApi) AddPhoneNumber(ctx context.Context, phoneNumber string, expireAt *Date, opts ...CallOption) (*BlackListPhoneNumber, error) {
+	ctx = withOperation(ctx, "BlacklistApi.AddPhoneNumber")
+
 	var result = new(BlackListPhoneNumber)
 
+	phoneNumber, err := blacklistApi.client.normalizePhoneNumber(phoneNumber)
+
+	if err != nil {
+		return result, err
+	}
+
 	blackListPhoneNumber := BlackListPhoneNumber{
 		PhoneNumber: phoneNumber,
 		ExpireAt:    expireAt,
 	}
 
-	err := blacklistApi.client.Post(ctx, blacklistApiPath, result, blackListPhoneNumber)
+	err = blacklistApi.client.Post(ctx, blacklistApiPath, result, blackListPhoneNumber, opts...)
 
 	return result, err
 }
 
-func (blacklistApi *BlacklistApi) DeleteAllPhoneNumbers(ctx context.Context) error {
-	err := blacklistApi.client.Delete(ctx, blacklistApiPath)
+func (blacklistApi *BlacklistApi) DeleteAllPhoneNumbers(ctx context.Context, opts ...CallOption) error {
+	ctx = withOperation(ctx, "BlacklistApi.DeleteAllPhoneNumbers")
+
+	err := blacklistApi.client.Delete(ctx, blacklistApiPath, opts...)
 
 	return err
 }
 
 // ImportPhoneNumbers uploads a CSV file containing phone numbers to be added
 // to the blacklist. Accepts any io.Reader that yields CSV content.
-func (blacklistApi *BlacklistApi) ImportPhoneNumbers(ctx context.Context, csv io.Reader) error {
-	return blacklistApi.client.PostRaw(ctx, "/blacklist/phone_numbers/imports", csv, ContentTypeTextCsv, nil)
+func (blacklistApi *BlacklistApi) ImportPhoneNumbers(ctx context.Context, csv io.Reader, opts ...CallOption) error {
+	ctx = withOperation(ctx, "BlacklistApi.ImportPhoneNumbers")
+
+	return blacklistApi.client.PostRaw(ctx, "/blacklist/phone_numbers/imports", csv, ContentTypeTextCsv, nil, opts...)
 }
 
 // ImportPhoneNumbersCsv is a convenience wrapper for ImportPhoneNumbers
 // taking a raw CSV string.
-func (blacklistApi *BlacklistApi) ImportPhoneNumbersCsv(ctx context.Context, csv string) error {
-	return blacklistApi.ImportPhoneNumbers(ctx, strings.NewReader(csv))
+func (blacklistApi *BlacklistApi) ImportPhoneNumbersCsv(ctx context.Context, csv string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "BlacklistApi.ImportPhoneNumbersCsv")
+
+	return blacklistApi.ImportPhoneNumbers(ctx, strings.NewReader(csv), opts...)
 }
 
-func (blacklistApi *BlacklistApi) DeletePhoneNumber(ctx context.Context, id string) error {
+func (blacklistApi *BlacklistApi) DeletePhoneNumber(ctx context.Context, id string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "BlacklistApi.DeletePhoneNumber")
+
 	uri := fmt.Sprintf("%s/%s", blacklistApiPath, id)
 
-	err := blacklistApi.client.Delete(ctx, uri)
+	err := blacklistApi.client.Delete(ctx, uri, opts...)
 
 	return err
 }
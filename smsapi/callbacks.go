@@ -2,7 +2,11 @@ package smsapi
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"iter"
+	"net/url"
 )
 
 const callbacksApiPath = "/callbacks"
@@ -40,50 +44,102 @@ type CallbackTestResult struct {
 	HttpResponseBody   string `json:"http_response_body,omitempty"`
 }
 
-func (api *CallbacksApi) List(ctx context.Context) (*CallbackCollection, error) {
+func (api *CallbacksApi) List(ctx context.Context, opts ...CallOption) (*CallbackCollection, error) {
+	ctx = withOperation(ctx, "CallbacksApi.List")
 	result := new(CallbackCollection)
-	err := api.client.Get(ctx, callbacksApiPath, result)
+	err := api.client.Get(ctx, callbacksApiPath, result, opts...)
 	return result, err
 }
 
-func (api *CallbacksApi) Get(ctx context.Context, id string) (*Callback, error) {
+// All iterates over registered callbacks.
+func (api *CallbacksApi) All(ctx context.Context, opts ...CallOption) iter.Seq2[*Callback, error] {
+	ctx = withOperation(ctx, "CallbacksApi.All")
+	return Paginate[Callback](ctx, api.client, callbacksApiPath, nil, opts...)
+}
+
+func (api *CallbacksApi) Get(ctx context.Context, id string, opts ...CallOption) (*Callback, error) {
+	ctx = withOperation(ctx, "CallbacksApi.Get")
 	result := new(Callback)
 	uri := fmt.Sprintf("%s/%s", callbacksApiPath, id)
-	err := api.client.Get(ctx, uri, result)
+	err := api.client.Get(ctx, uri, result, opts...)
 	return result, err
 }
 
-func (api *CallbacksApi) Create(ctx context.Context, callback *Callback) (*Callback, error) {
+func (api *CallbacksApi) Create(ctx context.Context, callback *Callback, opts ...CallOption) (*Callback, error) {
+	ctx = withOperation(ctx, "CallbacksApi.Create")
 	result := new(Callback)
-	err := api.client.Post(ctx, callbacksApiPath, result, callback)
+	err := api.client.Post(ctx, callbacksApiPath, result, callback, opts...)
 	return result, err
 }
 
-func (api *CallbacksApi) Update(ctx context.Context, id, url string) (*Callback, error) {
+// CreateSigned registers the callback with the secret token added to its url,
+// see SignCallbackUrl. The callback is left unchanged.
+func (api *CallbacksApi) CreateSigned(ctx context.Context, callback *Callback, token string, opts ...CallOption) (*Callback, error) {
+	ctx = withOperation(ctx, "CallbacksApi.CreateSigned")
+	signedUrl, err := SignCallbackUrl(callback.Url, token)
+	if err != nil {
+		return nil, err
+	}
+	signed := *callback
+	signed.Url = signedUrl
+	return api.Create(ctx, &signed, opts...)
+}
+
+// CallbackTokenParam is the query parameter carrying the secret token of signed callback urls.
+const CallbackTokenParam = "token"
+
+// NewCallbackToken returns a random secret token for signed callback urls.
+func NewCallbackToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// SignCallbackUrl adds the secret token to the callback url as CallbackTokenParam,
+// so that receivers can verify callbacks come from SMSAPI, e.g. with webhook.Guard.
+func SignCallbackUrl(callbackUrl, token string) (string, error) {
+	u, err := url.Parse(callbackUrl)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set(CallbackTokenParam, token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func (api *CallbacksApi) Update(ctx context.Context, id, url string, opts ...CallOption) (*Callback, error) {
+	ctx = withOperation(ctx, "CallbacksApi.Update")
 	result := new(Callback)
 	uri := fmt.Sprintf("%s/%s", callbacksApiPath, id)
-	err := api.client.Put(ctx, uri, result, &UpdateCallback{Url: url})
+	err := api.client.Put(ctx, uri, result, &UpdateCallback{Url: url}, opts...)
 	return result, err
 }
 
-func (api *CallbacksApi) Delete(ctx context.Context, id string) error {
+func (api *CallbacksApi) Delete(ctx context.Context, id string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "CallbacksApi.Delete")
 	uri := fmt.Sprintf("%s/%s", callbacksApiPath, id)
-	return api.client.Delete(ctx, uri)
+	return api.client.Delete(ctx, uri, opts...)
 }
 
-func (api *CallbacksApi) Activate(ctx context.Context, id string) error {
+func (api *CallbacksApi) Activate(ctx context.Context, id string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "CallbacksApi.Activate")
 	uri := fmt.Sprintf("%s/%s/commands/activate", callbacksApiPath, id)
-	return api.client.Put(ctx, uri, nil, nil)
+	return api.client.Put(ctx, uri, nil, nil, opts...)
 }
 
-func (api *CallbacksApi) Deactivate(ctx context.Context, id string) error {
+func (api *CallbacksApi) Deactivate(ctx context.Context, id string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "CallbacksApi.Deactivate")
 	uri := fmt.Sprintf("%s/%s/commands/deactivate", callbacksApiPath, id)
-	return api.client.Put(ctx, uri, nil, nil)
+	return api.client.Put(ctx, uri, nil, nil, opts...)
 }
 
-func (api *CallbacksApi) Test(ctx context.Context, id string) (*CallbackTestResult, error) {
+func (api *CallbacksApi) Test(ctx context.Context, id string, opts ...CallOption) (*CallbackTestResult, error) {
+	ctx = withOperation(ctx, "CallbacksApi.Test")
 	result := new(CallbackTestResult)
 	uri := fmt.Sprintf("%s/%s/commands/test", callbacksApiPath, id)
-	err := api.client.Get(ctx, uri, result)
+	err := api.client.Get(ctx, uri, result, opts...)
 	return result, err
 }
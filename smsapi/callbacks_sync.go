@@ -0,0 +1,201 @@
+package smsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrDuplicateCallback = errors.New("smsapi: duplicate desired callback")
+
+type CallbackSyncAction string
+
+const (
+	CallbackSyncCreate     = CallbackSyncAction("create")
+	CallbackSyncUpdate     = CallbackSyncAction("update")
+	CallbackSyncActivate   = CallbackSyncAction("activate")
+	CallbackSyncDeactivate = CallbackSyncAction("deactivate")
+	CallbackSyncDelete     = CallbackSyncAction("delete")
+)
+
+// CallbackSyncOptions configures CallbacksApi.Sync, nil means changes are applied
+// without deleting or testing callbacks.
+type CallbackSyncOptions struct {
+	// Prune deletes existing callbacks not matching any desired one.
+	Prune bool
+	// DryRun only plans the changes.
+	DryRun bool
+	// Test runs CallbacksApi.Test on every desired callback after the changes.
+	Test bool
+}
+
+type CallbackSyncChange struct {
+	Action CallbackSyncAction
+	// Callback is the desired callback, or the existing one for CallbackSyncDelete.
+	Callback *Callback
+	// Existing is the registered callback the change applies to, nil for CallbackSyncCreate.
+	Existing *Callback
+	Err      error
+}
+
+type CallbackTestFailure struct {
+	Callback *Callback
+	// Result is nil when the test could not be run.
+	Result *CallbackTestResult
+	Err    error
+}
+
+type CallbackSyncPlan struct {
+	Changes []*CallbackSyncChange
+	// Callbacks are the registered callbacks matching the desired ones, in their order.
+	// They are not known in a dry run for callbacks to be created.
+	Callbacks    []*Callback
+	TestFailures []*CallbackTestFailure
+}
+
+// Sync makes registered callbacks match the desired ones. Callbacks are matched by Type and
+// ReceiverNumber, matching callbacks get their Url updated and are activated or deactivated
+// according to Active, which is the desired state, so it has to be set for active callbacks.
+//
+// All changes are attempted, the returned error joins errors of failed changes.
+func (api *CallbacksApi) Sync(ctx context.Context, desired []*Callback, options *CallbackSyncOptions, opts ...CallOption) (*CallbackSyncPlan, error) {
+	ctx = withOperation(ctx, "CallbacksApi.Sync")
+	o := CallbackSyncOptions{}
+	if options != nil {
+		o = *options
+	}
+	existing := map[string]*Callback{}
+	var all []*Callback
+	for callback, err := range api.All(ctx, opts...) {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, callback)
+		if _, ok := existing[callbackKey(callback)]; !ok {
+			existing[callbackKey(callback)] = callback
+		}
+	}
+	plan := &CallbackSyncPlan{}
+	wanted := map[string]bool{}
+	for _, callback := range desired {
+		key := callbackKey(callback)
+		if wanted[key] {
+			return nil, fmt.Errorf("%w: %s %s", ErrDuplicateCallback, callback.Type, callback.ReceiverNumber)
+		}
+		wanted[key] = true
+		plan.Changes = append(plan.Changes, planCallback(callback, existing[key])...)
+	}
+	// Callbacks not matching desired ones and duplicates are deleted in the order they are listed.
+	var extra []*Callback
+	for _, callback := range all {
+		key := callbackKey(callback)
+		if !wanted[key] || existing[key] != callback {
+			extra = append(extra, callback)
+		}
+	}
+	if o.Prune {
+		for _, callback := range extra {
+			plan.Changes = append(plan.Changes, &CallbackSyncChange{Action: CallbackSyncDelete, Callback: callback, Existing: callback})
+		}
+	}
+	if o.DryRun {
+		for _, callback := range desired {
+			if current, ok := existing[callbackKey(callback)]; ok {
+				plan.Callbacks = append(plan.Callbacks, current)
+			}
+		}
+		return plan, nil
+	}
+	var errs []error
+	registered := map[string]*Callback{}
+	for key, callback := range existing {
+		registered[key] = callback
+	}
+	for _, change := range plan.Changes {
+		change.Err = api.applyChange(ctx, change, registered, opts)
+		errs = append(errs, change.Err)
+	}
+	for _, callback := range desired {
+		if current, ok := registered[callbackKey(callback)]; ok {
+			plan.Callbacks = append(plan.Callbacks, current)
+		}
+	}
+	if o.Test {
+		plan.TestFailures = api.testCallbacks(ctx, plan.Callbacks, opts)
+	}
+	return plan, errors.Join(errs...)
+}
+
+func (api *CallbacksApi) applyChange(ctx context.Context, change *CallbackSyncChange, registered map[string]*Callback, opts []CallOption) error {
+	key := callbackKey(change.Callback)
+	switch change.Action {
+	case CallbackSyncCreate:
+		result, err := api.Create(ctx, change.Callback, opts...)
+		if err != nil {
+			return err
+		}
+		registered[key] = result
+		if result.Active != change.Callback.Active {
+			return api.setActive(ctx, result, change.Callback.Active, opts)
+		}
+	case CallbackSyncUpdate:
+		result, err := api.Update(ctx, registered[key].Id, change.Callback.Url, opts...)
+		if err != nil {
+			return err
+		}
+		registered[key] = result
+	case CallbackSyncActivate, CallbackSyncDeactivate:
+		return api.setActive(ctx, registered[key], change.Action == CallbackSyncActivate, opts)
+	case CallbackSyncDelete:
+		return api.Delete(ctx, change.Callback.Id, opts...)
+	}
+	return nil
+}
+
+func (api *CallbacksApi) setActive(ctx context.Context, callback *Callback, active bool, opts []CallOption) error {
+	var err error
+	if active {
+		err = api.Activate(ctx, callback.Id, opts...)
+	} else {
+		err = api.Deactivate(ctx, callback.Id, opts...)
+	}
+	if err == nil {
+		callback.Active = active
+	}
+	return err
+}
+
+func (api *CallbacksApi) testCallbacks(ctx context.Context, callbacks []*Callback, opts []CallOption) []*CallbackTestFailure {
+	var failures []*CallbackTestFailure
+	for _, callback := range callbacks {
+		result, err := api.Test(ctx, callback.Id, opts...)
+		if err != nil {
+			failures = append(failures, &CallbackTestFailure{Callback: callback, Err: err})
+		} else if !result.TestResult {
+			failures = append(failures, &CallbackTestFailure{Callback: callback, Result: result})
+		}
+	}
+	return failures
+}
+
+func planCallback(desired, existing *Callback) []*CallbackSyncChange {
+	if existing == nil {
+		return []*CallbackSyncChange{{Action: CallbackSyncCreate, Callback: desired}}
+	}
+	var changes []*CallbackSyncChange
+	if existing.Url != desired.Url {
+		changes = append(changes, &CallbackSyncChange{Action: CallbackSyncUpdate, Callback: desired, Existing: existing})
+	}
+	if existing.Active != desired.Active {
+		action := CallbackSyncDeactivate
+		if desired.Active {
+			action = CallbackSyncActivate
+		}
+		changes = append(changes, &CallbackSyncChange{Action: action, Callback: desired, Existing: existing})
+	}
+	return changes
+}
+
+func callbackKey(callback *Callback) string {
+	return callback.Type + "|" + callback.ReceiverNumber
+}
@@ -0,0 +1,238 @@
+package smsapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+const syncExisting = `{"size":3,"collection":[
+	{"id":"1","url":"https://example.com/dlr","type":"sms","active":true},
+	{"id":"2","url":"https://old.example.com/mo","type":"sms_mo","active":false,"receiver_number":"48500000000"},
+	{"id":"3","url":"https://example.com/mms","type":"mms","active":true}
+]}`
+
+func serveSync(mux *http.ServeMux, testResult bool) *[]string {
+	var mu sync.Mutex
+	var calls []string
+
+	record := func(r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls = append(calls, r.Method+" "+r.URL.Path)
+	}
+
+	mux.HandleFunc("/callbacks", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, syncExisting)
+
+			return
+		}
+
+		record(r)
+		fmt.Fprint(w, `{"id":"4","url":"https://example.com/vms","type":"vms","active":false}`)
+	})
+	mux.HandleFunc("/callbacks/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/callbacks/2" && r.Method == "PUT" {
+			fmt.Fprint(w, `{"id":"2","url":"https://example.com/mo","type":"sms_mo","active":false,"receiver_number":"48500000000"}`)
+		}
+
+		if r.Method == "GET" {
+			fmt.Fprintf(w, `{"test_result":%t,"connection_failed":false,"invalid_encoding":false}`, testResult)
+
+			return
+		}
+
+		record(r)
+	})
+
+	return &calls
+}
+
+func syncDesired() []*Callback {
+	return []*Callback{
+		{Url: "https://example.com/dlr", Type: "sms", Active: true},
+		{Url: "https://example.com/mo", Type: "sms_mo", Active: true, ReceiverNumber: "48500000000"},
+		{Url: "https://example.com/vms", Type: "vms", Active: true},
+	}
+}
+
+func TestCallbacksSync(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := serveSync(mux, true)
+
+	plan, err := client.Callbacks.Sync(ctx, syncDesired(), &CallbackSyncOptions{Prune: true, Test: true})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"PUT /callbacks/2",
+		"PUT /callbacks/2/commands/activate",
+		"POST /callbacks",
+		"PUT /callbacks/4/commands/activate",
+		"DELETE /callbacks/3",
+	}
+
+	if fmt.Sprint(*calls) != fmt.Sprint(expected) {
+		t.Errorf("Expected calls %v, given %v", expected, *calls)
+	}
+
+	actions := []CallbackSyncAction{CallbackSyncUpdate, CallbackSyncActivate, CallbackSyncCreate, CallbackSyncDelete}
+
+	if len(plan.Changes) != len(actions) {
+		t.Fatalf("Unexpected changes: %+v", plan.Changes)
+	}
+
+	for i, change := range plan.Changes {
+		if change.Action != actions[i] || change.Err != nil {
+			t.Errorf("Unexpected change %d: %+v", i, change)
+		}
+	}
+
+	if len(plan.Callbacks) != 3 || plan.Callbacks[1].Url != "https://example.com/mo" || !plan.Callbacks[1].Active || !plan.Callbacks[2].Active {
+		t.Errorf("Unexpected callbacks: %+v", plan.Callbacks)
+	}
+
+	if len(plan.TestFailures) != 0 {
+		t.Errorf("Unexpected test failures: %+v", plan.TestFailures)
+	}
+}
+
+func TestCallbacksSyncDryRun(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := serveSync(mux, true)
+
+	plan, err := client.Callbacks.Sync(ctx, syncDesired(), &CallbackSyncOptions{DryRun: true})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*calls) != 0 {
+		t.Errorf("Expected no changes in a dry run, given %v", *calls)
+	}
+
+	// Without Prune the mms callback is left untouched.
+	if len(plan.Changes) != 3 || plan.Changes[2].Action != CallbackSyncCreate {
+		t.Errorf("Unexpected changes: %+v", plan.Changes)
+	}
+
+	if len(plan.Callbacks) != 2 {
+		t.Errorf("Unexpected callbacks: %+v", plan.Callbacks)
+	}
+}
+
+func TestCallbacksSyncPruneOrder(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/callbacks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"size":6,"collection":[
+			{"id":"5","url":"https://example.com/a","type":"sms"},
+			{"id":"2","url":"https://example.com/b","type":"mms"},
+			{"id":"6","url":"https://example.com/c","type":"vms"},
+			{"id":"1","url":"https://example.com/d","type":"sms"},
+			{"id":"4","url":"https://example.com/e","type":"sms_mo"},
+			{"id":"3","url":"https://example.com/f","type":"mms_mo"}
+		]}`)
+	})
+
+	desired := []*Callback{{Url: "https://example.com/a", Type: "sms"}}
+
+	for i := 0; i < 10; i++ {
+		plan, err := client.Callbacks.Sync(ctx, desired, &CallbackSyncOptions{Prune: true, DryRun: true})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ids []string
+
+		for _, change := range plan.Changes {
+			ids = append(ids, change.Callback.Id)
+		}
+
+		if fmt.Sprint(ids) != "[2 6 1 4 3]" {
+			t.Fatalf("Expected deletes in list order, given %v", ids)
+		}
+	}
+}
+
+func TestCallbacksSyncTestFailures(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	serveSync(mux, false)
+
+	desired := syncDesired()[:1]
+
+	plan, err := client.Callbacks.Sync(ctx, desired, &CallbackSyncOptions{Test: true})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Changes) != 0 {
+		t.Errorf("Unexpected changes: %+v", plan.Changes)
+	}
+
+	if len(plan.TestFailures) != 1 || plan.TestFailures[0].Callback.Id != "1" || plan.TestFailures[0].Result == nil {
+		t.Errorf("Unexpected test failures: %+v", plan.TestFailures)
+	}
+}
+
+func TestCallbacksSyncDuplicate(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := serveSync(mux, true)
+
+	desired := append(syncDesired(), &Callback{Url: "https://example.com/other", Type: "sms"})
+
+	_, err := client.Callbacks.Sync(ctx, desired, nil)
+
+	if !errors.Is(err, ErrDuplicateCallback) {
+		t.Errorf("Expected ErrDuplicateCallback, given %v", err)
+	}
+
+	if len(*calls) != 0 {
+		t.Errorf("Expected no changes, given %v", *calls)
+	}
+}
+
+func TestCallbacksSyncErrors(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/callbacks", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"size":0,"collection":[]}`)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"invalid_request_data","message":"Invalid url"}`)
+	})
+
+	plan, err := client.Callbacks.Sync(ctx, syncDesired(), nil)
+
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+
+	for _, change := range plan.Changes {
+		if change.Err == nil {
+			t.Errorf("Expected change error: %+v", change)
+		}
+	}
+}
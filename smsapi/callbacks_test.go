@@ -1,6 +1,7 @@
 package smsapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
@@ -137,3 +138,35 @@ func TestCallbacksTest(t *testing.T) {
 		t.Errorf("Unexpected: %+v", result)
 	}
 }
+
+func TestCallbacksCreateSigned(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/callbacks", func(w http.ResponseWriter, r *http.Request) {
+		callback := new(Callback)
+		json.NewDecoder(r.Body).Decode(callback)
+
+		if callback.Url != "https://example.com/dlr?source=smsapi&token=secret" {
+			t.Errorf("Unexpected url: %s", callback.Url)
+		}
+
+		fmt.Fprint(w, `{"id":"1"}`)
+	})
+
+	callback := &Callback{Url: "https://example.com/dlr?source=smsapi", Type: "sms"}
+
+	if _, err := client.Callbacks.CreateSigned(ctx, callback, "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	if callback.Url != "https://example.com/dlr?source=smsapi" {
+		t.Errorf("Expected callback to be left unchanged, given: %s", callback.Url)
+	}
+
+	token, _ := NewCallbackToken()
+
+	if len(token) != 64 {
+		t.Errorf("Unexpected token: %s", token)
+	}
+}
@@ -3,6 +3,7 @@ package smsapi
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -65,59 +66,109 @@ func (b *ContactsCollectionIterator) Next() (*ContactCollectionResponse, error)
 	return c, nil
 }
 
-func (contactsApi *ContactsApi) GetContacts(ctx context.Context, filters *ContactListFilters) (*ContactCollectionResponse, error) {
+func (contactsApi *ContactsApi) GetContacts(ctx context.Context, filters *ContactListFilters, opts ...CallOption) (*ContactCollectionResponse, error) {
+	ctx = withOperation(ctx, "ContactsApi.GetContacts")
+
 	var result = new(ContactCollectionResponse)
 
 	uri, _ := addQueryParams("/contacts", filters)
 
-	err := contactsApi.client.Get(ctx, uri, result)
+	err := contactsApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) GetContactsPageIterator(ctx context.Context, filters *ContactListFilters) *ContactsCollectionIterator {
-	i := NewPageIterator(contactsApi.client, ctx, contactsApiPath, filters)
+func (contactsApi *ContactsApi) GetContactsPageIterator(ctx context.Context, filters *ContactListFilters, opts ...CallOption) *ContactsCollectionIterator {
+	ctx = withOperation(ctx, "ContactsApi.GetContactsPageIterator")
+
+	i := NewPageIterator(contactsApi.client, ctx, contactsApiPath, filters, opts...)
 	ci := &ContactsCollectionIterator{i}
 
 	return ci
 }
 
-func (contactsApi *ContactsApi) CreateContact(ctx context.Context, contact *Contact) (*Contact, error) {
+// All iterates over contacts matching the filters, fetching further pages on demand.
+func (contactsApi *ContactsApi) All(ctx context.Context, filters *ContactListFilters, opts ...CallOption) iter.Seq2[*Contact, error] {
+	ctx = withOperation(ctx, "ContactsApi.All")
+
+	return Paginate[Contact](ctx, contactsApi.client, contactsApiPath, filters, opts...)
+}
+
+func (contactsApi *ContactsApi) CreateContact(ctx context.Context, contact *Contact, opts ...CallOption) (*Contact, error) {
+	ctx = withOperation(ctx, "ContactsApi.CreateContact")
+
 	var result = new(Contact)
 
-	err := contactsApi.client.Urlencoded(ctx, http.MethodPost, contactsApiPath, result, contact)
+	contact, err := contactsApi.normalizeContact(contact)
+
+	if err != nil {
+		return result, err
+	}
+
+	err = contactsApi.client.Urlencoded(ctx, http.MethodPost, contactsApiPath, result, contact, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) DeleteAllContacts(ctx context.Context) error {
-	return contactsApi.client.Delete(ctx, contactsApiPath)
+func (contactsApi *ContactsApi) DeleteAllContacts(ctx context.Context, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.DeleteAllContacts")
+
+	return contactsApi.client.Delete(ctx, contactsApiPath, opts...)
 }
 
-func (contactsApi *ContactsApi) GetContact(ctx context.Context, id string) (*Contact, error) {
+func (contactsApi *ContactsApi) GetContact(ctx context.Context, id string, opts ...CallOption) (*Contact, error) {
+	ctx = withOperation(ctx, "ContactsApi.GetContact")
+
 	uri := fmt.Sprintf("%s/%s", contactsApiPath, id)
 
 	var result = new(Contact)
 
-	err := contactsApi.client.Get(ctx, uri, result)
+	err := contactsApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) UpdateContact(ctx context.Context, id string, contact *Contact) (*Contact, error) {
+func (contactsApi *ContactsApi) UpdateContact(ctx context.Context, id string, contact *Contact, opts ...CallOption) (*Contact, error) {
+	ctx = withOperation(ctx, "ContactsApi.UpdateContact")
+
 	uri := fmt.Sprintf("/contacts/%s", id)
 
 	var result = new(Contact)
 
-	err := contactsApi.client.Urlencoded(ctx, http.MethodPut, uri, result, contact)
+	contact, err := contactsApi.normalizeContact(contact)
+
+	if err != nil {
+		return result, err
+	}
+
+	err = contactsApi.client.Urlencoded(ctx, http.MethodPut, uri, result, contact, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) DeleteContact(ctx context.Context, id string) error {
+func (contactsApi *ContactsApi) normalizeContact(contact *Contact) (*Contact, error) {
+	if contact == nil {
+		return contact, nil
+	}
+
+	phoneNumber, err := contactsApi.client.normalizePhoneNumber(contact.PhoneNumber)
+
+	if err != nil || phoneNumber == contact.PhoneNumber {
+		return contact, err
+	}
+
+	normalized := *contact
+	normalized.PhoneNumber = phoneNumber
+
+	return &normalized, nil
+}
+
+func (contactsApi *ContactsApi) DeleteContact(ctx context.Context, id string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.DeleteContact")
+
 	uri := fmt.Sprintf("/contacts/%s", id)
 
-	err := contactsApi.client.Delete(ctx, uri)
+	err := contactsApi.client.Delete(ctx, uri, opts...)
 
 	return err
 }
@@ -147,136 +198,168 @@ type ContactGroupsCollectionResponse struct {
 	Collection []*ContactGroup `json:"collection"`
 }
 
-func (contactsApi *ContactsApi) GetContactGroups(ctx context.Context, id string) (*ContactGroupsCollectionResponse, error) {
+func (contactsApi *ContactsApi) GetContactGroups(ctx context.Context, id string, opts ...CallOption) (*ContactGroupsCollectionResponse, error) {
+	ctx = withOperation(ctx, "ContactsApi.GetContactGroups")
+
 	uri := fmt.Sprintf("/contacts/%s/groups", id)
 
 	var result = new(ContactGroupsCollectionResponse)
 
-	err := contactsApi.client.Get(ctx, uri, result)
+	err := contactsApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) GetContactGroup(ctx context.Context, contactId string, groupId string) (*ContactGroup, error) {
+func (contactsApi *ContactsApi) GetContactGroup(ctx context.Context, contactId string, groupId string, opts ...CallOption) (*ContactGroup, error) {
+	ctx = withOperation(ctx, "ContactsApi.GetContactGroup")
+
 	uri := fmt.Sprintf("/contacts/%s/groups/%s", contactId, groupId)
 
 	var result = new(ContactGroup)
 
-	err := contactsApi.client.Get(ctx, uri, result)
+	err := contactsApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) BindContactToGroup(ctx context.Context, contactId string, groupId string) (*ContactGroupsCollectionResponse, error) {
+func (contactsApi *ContactsApi) BindContactToGroup(ctx context.Context, contactId string, groupId string, opts ...CallOption) (*ContactGroupsCollectionResponse, error) {
+	ctx = withOperation(ctx, "ContactsApi.BindContactToGroup")
+
 	uri := fmt.Sprintf("/contacts/%s/groups/%s", contactId, groupId)
 
 	var result = new(ContactGroupsCollectionResponse)
 
-	err := contactsApi.client.Put(ctx, uri, result, nil)
+	err := contactsApi.client.Put(ctx, uri, result, nil, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) UnbindContactFromGroup(ctx context.Context, contactId string, groupId string) error {
+func (contactsApi *ContactsApi) UnbindContactFromGroup(ctx context.Context, contactId string, groupId string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.UnbindContactFromGroup")
+
 	uri := fmt.Sprintf("/contacts/%s/groups/%s", contactId, groupId)
 
-	err := contactsApi.client.Delete(ctx, uri)
+	err := contactsApi.client.Delete(ctx, uri, opts...)
 
 	return err
 }
 
-func (contactsApi *ContactsApi) GetGroups(ctx context.Context) (*ContactGroupsCollectionResponse, error) {
+func (contactsApi *ContactsApi) GetGroups(ctx context.Context, opts ...CallOption) (*ContactGroupsCollectionResponse, error) {
+	ctx = withOperation(ctx, "ContactsApi.GetGroups")
+
 	var result = new(ContactGroupsCollectionResponse)
 
-	err := contactsApi.client.Get(ctx, "contacts/groups", result)
+	err := contactsApi.client.Get(ctx, "contacts/groups", result, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) CreateGroup(ctx context.Context, group *ContactGroup) (*ContactGroup, error) {
+func (contactsApi *ContactsApi) CreateGroup(ctx context.Context, group *ContactGroup, opts ...CallOption) (*ContactGroup, error) {
+	ctx = withOperation(ctx, "ContactsApi.CreateGroup")
+
 	var result = new(ContactGroup)
 
-	err := contactsApi.client.Urlencoded(ctx, http.MethodPost, "contacts/groups", result, group)
+	err := contactsApi.client.Urlencoded(ctx, http.MethodPost, "contacts/groups", result, group, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) DeleteAllGroup(ctx context.Context) error {
-	err := contactsApi.client.Delete(ctx, "contacts/groups")
+func (contactsApi *ContactsApi) DeleteAllGroup(ctx context.Context, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.DeleteAllGroup")
+
+	err := contactsApi.client.Delete(ctx, "contacts/groups", opts...)
 
 	return err
 }
 
-func (contactsApi *ContactsApi) UpdateGroup(ctx context.Context, groupId string, group *ContactGroup) (*ContactGroup, error) {
+func (contactsApi *ContactsApi) UpdateGroup(ctx context.Context, groupId string, group *ContactGroup, opts ...CallOption) (*ContactGroup, error) {
+	ctx = withOperation(ctx, "ContactsApi.UpdateGroup")
+
 	uri := fmt.Sprintf("contacts/groups/%s", groupId)
 
 	var result = new(ContactGroup)
 
-	err := contactsApi.client.Urlencoded(ctx, http.MethodPut, uri, result, group)
+	err := contactsApi.client.Urlencoded(ctx, http.MethodPut, uri, result, group, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) GetGroup(ctx context.Context, groupId string) (*ContactGroup, error) {
+func (contactsApi *ContactsApi) GetGroup(ctx context.Context, groupId string, opts ...CallOption) (*ContactGroup, error) {
+	ctx = withOperation(ctx, "ContactsApi.GetGroup")
+
 	uri := fmt.Sprintf("contacts/groups/%s", groupId)
 
 	var result = new(ContactGroup)
 
-	err := contactsApi.client.Get(ctx, uri, result)
+	err := contactsApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) DeleteGroup(ctx context.Context, groupId string) error {
+func (contactsApi *ContactsApi) DeleteGroup(ctx context.Context, groupId string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.DeleteGroup")
+
 	uri := fmt.Sprintf("contacts/groups/%s", groupId)
 
-	err := contactsApi.client.Delete(ctx, uri)
+	err := contactsApi.client.Delete(ctx, uri, opts...)
 
 	return err
 }
 
-func (contactsApi *ContactsApi) MoveContactsToGroup(ctx context.Context, groupId string, filters *ContactListFilters) error {
+func (contactsApi *ContactsApi) MoveContactsToGroup(ctx context.Context, groupId string, filters *ContactListFilters, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.MoveContactsToGroup")
+
 	uri := fmt.Sprintf("contacts/groups/%s/members", groupId)
 
-	return contactsApi.client.Urlencoded(ctx, http.MethodPut, uri, nil, filters)
+	return contactsApi.client.Urlencoded(ctx, http.MethodPut, uri, nil, filters, opts...)
 }
 
-func (contactsApi *ContactsApi) AddContactsToGroup(ctx context.Context, groupId string, filters *ContactListFilters) error {
+func (contactsApi *ContactsApi) AddContactsToGroup(ctx context.Context, groupId string, filters *ContactListFilters, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.AddContactsToGroup")
+
 	uri := fmt.Sprintf("contacts/groups/%s/members", groupId)
 
-	return contactsApi.client.Urlencoded(ctx, http.MethodPost, uri, nil, filters)
+	return contactsApi.client.Urlencoded(ctx, http.MethodPost, uri, nil, filters, opts...)
 }
 
-func (contactsApi *ContactsApi) RemoveContactsFromGroup(ctx context.Context, groupId string, filters *ContactListFilters) error {
+func (contactsApi *ContactsApi) RemoveContactsFromGroup(ctx context.Context, groupId string, filters *ContactListFilters, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.RemoveContactsFromGroup")
+
 	uri := fmt.Sprintf("contacts/groups/%s/members", groupId)
 
-	return contactsApi.client.Urlencoded(ctx, http.MethodDelete, uri, nil, filters)
+	return contactsApi.client.Urlencoded(ctx, http.MethodDelete, uri, nil, filters, opts...)
 }
 
-func (contactsApi *ContactsApi) AddContactToGroup(ctx context.Context, groupId, contactId string) (*Contact, error) {
+func (contactsApi *ContactsApi) AddContactToGroup(ctx context.Context, groupId, contactId string, opts ...CallOption) (*Contact, error) {
+	ctx = withOperation(ctx, "ContactsApi.AddContactToGroup")
+
 	uri := fmt.Sprintf("contacts/groups/%s/members/%s", groupId, contactId)
 
 	var result = new(Contact)
 
-	err := contactsApi.client.Put(ctx, uri, result, nil)
+	err := contactsApi.client.Put(ctx, uri, result, nil, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) GetContactFromGroup(ctx context.Context, groupId, contactId string) (*Contact, error) {
+func (contactsApi *ContactsApi) GetContactFromGroup(ctx context.Context, groupId, contactId string, opts ...CallOption) (*Contact, error) {
+	ctx = withOperation(ctx, "ContactsApi.GetContactFromGroup")
+
 	uri := fmt.Sprintf("contacts/groups/%s/members/%s", groupId, contactId)
 
 	var result = new(Contact)
 
-	err := contactsApi.client.Get(ctx, uri, result)
+	err := contactsApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) RemoveContactFromGroup(ctx context.Context, groupId, contactId string) error {
+func (contactsApi *ContactsApi) RemoveContactFromGroup(ctx context.Context, groupId, contactId string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.RemoveContactFromGroup")
+
 	uri := fmt.Sprintf("contacts/groups/%s/members/%s", groupId, contactId)
 
-	err := contactsApi.client.Delete(ctx, uri)
+	err := contactsApi.client.Delete(ctx, uri, opts...)
 
 	return err
 }
@@ -286,50 +369,60 @@ type ContactGroupPermissionsCollectionResponse struct {
 	Collection []ContactGroupPermissions `json:"collection"`
 }
 
-func (contactsApi *ContactsApi) GetGroupPermissions(ctx context.Context, groupId string) (*ContactGroupPermissionsCollectionResponse, error) {
+func (contactsApi *ContactsApi) GetGroupPermissions(ctx context.Context, groupId string, opts ...CallOption) (*ContactGroupPermissionsCollectionResponse, error) {
+	ctx = withOperation(ctx, "ContactsApi.GetGroupPermissions")
+
 	uri := fmt.Sprintf("contacts/groups/%s/permissions", groupId)
 
 	var result = new(ContactGroupPermissionsCollectionResponse)
 
-	err := contactsApi.client.Get(ctx, uri, result)
+	err := contactsApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) AddGroupPermissions(ctx context.Context, groupId string, permissions *ContactGroupPermissions) (*ContactGroupPermissions, error) {
+func (contactsApi *ContactsApi) AddGroupPermissions(ctx context.Context, groupId string, permissions *ContactGroupPermissions, opts ...CallOption) (*ContactGroupPermissions, error) {
+	ctx = withOperation(ctx, "ContactsApi.AddGroupPermissions")
+
 	uri := fmt.Sprintf("contacts/groups/%s/permissions", groupId)
 
 	var result = new(ContactGroupPermissions)
 
-	err := contactsApi.client.Urlencoded(ctx, http.MethodPost, uri, result, permissions)
+	err := contactsApi.client.Urlencoded(ctx, http.MethodPost, uri, result, permissions, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) GetUserGroupPermissions(ctx context.Context, groupId, username string) (*ContactGroupPermissions, error) {
+func (contactsApi *ContactsApi) GetUserGroupPermissions(ctx context.Context, groupId, username string, opts ...CallOption) (*ContactGroupPermissions, error) {
+	ctx = withOperation(ctx, "ContactsApi.GetUserGroupPermissions")
+
 	uri := fmt.Sprintf("contacts/groups/%s/permissions/%s", groupId, username)
 
 	var result = new(ContactGroupPermissions)
 
-	err := contactsApi.client.Get(ctx, uri, result)
+	err := contactsApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) AddUserGroupPermissions(ctx context.Context, groupId, username string, permissions *ContactGroupPermissions) (*ContactGroupPermissions, error) {
+func (contactsApi *ContactsApi) AddUserGroupPermissions(ctx context.Context, groupId, username string, permissions *ContactGroupPermissions, opts ...CallOption) (*ContactGroupPermissions, error) {
+	ctx = withOperation(ctx, "ContactsApi.AddUserGroupPermissions")
+
 	uri := fmt.Sprintf("contacts/groups/%s/permissions/%s", groupId, username)
 
 	var result = new(ContactGroupPermissions)
 
-	err := contactsApi.client.Urlencoded(ctx, http.MethodPut, uri, result, permissions)
+	err := contactsApi.client.Urlencoded(ctx, http.MethodPut, uri, result, permissions, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) RemoveUserGroupPermissions(ctx context.Context, groupId, username string) error {
+func (contactsApi *ContactsApi) RemoveUserGroupPermissions(ctx context.Context, groupId, username string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.RemoveUserGroupPermissions")
+
 	uri := fmt.Sprintf("contacts/groups/%s/permissions/%s", groupId, username)
 
-	err := contactsApi.client.Delete(ctx, uri)
+	err := contactsApi.client.Delete(ctx, uri, opts...)
 
 	return err
 }
@@ -345,15 +438,19 @@ type CustomFieldsCollectionResponse struct {
 	Collection []CustomField `json:"collection"`
 }
 
-func (contactsApi *ContactsApi) GetCustomFields(ctx context.Context) (*CustomFieldsCollectionResponse, error) {
+func (contactsApi *ContactsApi) GetCustomFields(ctx context.Context, opts ...CallOption) (*CustomFieldsCollectionResponse, error) {
+	ctx = withOperation(ctx, "ContactsApi.GetCustomFields")
+
 	var result = new(CustomFieldsCollectionResponse)
 
-	err := contactsApi.client.Get(ctx, "contacts/fields", result)
+	err := contactsApi.client.Get(ctx, "contacts/fields", result, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) CreateCustomField(ctx context.Context, name, type_ string) (*CustomField, error) {
+func (contactsApi *ContactsApi) CreateCustomField(ctx context.Context, name, type_ string, opts ...CallOption) (*CustomField, error) {
+	ctx = withOperation(ctx, "ContactsApi.CreateCustomField")
+
 	field := &CustomField{
 		Name: name,
 		Type: type_,
@@ -361,12 +458,14 @@ func (contactsApi *ContactsApi) CreateCustomField(ctx context.Context, name, typ
 
 	var result = new(CustomField)
 
-	err := contactsApi.client.Urlencoded(ctx, http.MethodPost, "contacts/fields", result, field)
+	err := contactsApi.client.Urlencoded(ctx, http.MethodPost, "contacts/fields", result, field, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) UpdateCustomField(ctx context.Context, fieldId, name string) (*CustomField, error) {
+func (contactsApi *ContactsApi) UpdateCustomField(ctx context.Context, fieldId, name string, opts ...CallOption) (*CustomField, error) {
+	ctx = withOperation(ctx, "ContactsApi.UpdateCustomField")
+
 	field := &CustomField{
 		Name: name,
 	}
@@ -375,15 +474,17 @@ func (contactsApi *ContactsApi) UpdateCustomField(ctx context.Context, fieldId,
 
 	var result = new(CustomField)
 
-	err := contactsApi.client.Urlencoded(ctx, http.MethodPut, uri, result, field)
+	err := contactsApi.client.Urlencoded(ctx, http.MethodPut, uri, result, field, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) DeleteCustomField(ctx context.Context, fieldId string) error {
+func (contactsApi *ContactsApi) DeleteCustomField(ctx context.Context, fieldId string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.DeleteCustomField")
+
 	uri := fmt.Sprintf("contacts/fields/%s", fieldId)
 
-	err := contactsApi.client.Delete(ctx, uri)
+	err := contactsApi.client.Delete(ctx, uri, opts...)
 
 	return err
 }
@@ -391,7 +492,9 @@ func (contactsApi *ContactsApi) DeleteCustomField(ctx context.Context, fieldId s
 // AssignContactToGroups assigns a contact to multiple groups at once.
 // The request body is urlencoded with numeric keys mapping to group ids
 // (e.g. 0=<groupId1>&1=<groupId2>).
-func (contactsApi *ContactsApi) AssignContactToGroups(ctx context.Context, contactId string, groupIds []string) (*ContactGroupsCollectionResponse, error) {
+func (contactsApi *ContactsApi) AssignContactToGroups(ctx context.Context, contactId string, groupIds []string, opts ...CallOption) (*ContactGroupsCollectionResponse, error) {
+	ctx = withOperation(ctx, "ContactsApi.AssignContactToGroups")
+
 	uri := fmt.Sprintf("/contacts/%s/groups", contactId)
 
 	values := url.Values{}
@@ -401,17 +504,21 @@ func (contactsApi *ContactsApi) AssignContactToGroups(ctx context.Context, conta
 
 	var result = new(ContactGroupsCollectionResponse)
 
-	err := contactsApi.client.PostRaw(ctx, uri, strings.NewReader(values.Encode()), ContentTypeXFormUrlencoded, result)
+	err := contactsApi.client.PostRaw(ctx, uri, strings.NewReader(values.Encode()), ContentTypeXFormUrlencoded, result, opts...)
 
 	return result, err
 }
 
-func (contactsApi *ContactsApi) CleanTrash(ctx context.Context) error {
-	return contactsApi.client.Delete(ctx, "/contacts/trash")
+func (contactsApi *ContactsApi) CleanTrash(ctx context.Context, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.CleanTrash")
+
+	return contactsApi.client.Delete(ctx, "/contacts/trash", opts...)
 }
 
-func (contactsApi *ContactsApi) RestoreTrash(ctx context.Context) error {
-	return contactsApi.client.Urlencoded(ctx, http.MethodPut, "/contacts/trash/restore", nil, nil)
+func (contactsApi *ContactsApi) RestoreTrash(ctx context.Context, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ContactsApi.RestoreTrash")
+
+	return contactsApi.client.Urlencoded(ctx, http.MethodPut, "/contacts/trash/restore", nil, nil, opts...)
 }
 
 type FieldOption struct {
@@ -424,12 +531,14 @@ type FieldOptionsCollectionResponse struct {
 	Collection []*FieldOption `json:"collection"`
 }
 
-func (contactsApi *ContactsApi) GetCustomFieldOptions(ctx context.Context, fieldId string) (*FieldOptionsCollectionResponse, error) {
+func (contactsApi *ContactsApi) GetCustomFieldOptions(ctx context.Context, fieldId string, opts ...CallOption) (*FieldOptionsCollectionResponse, error) {
+	ctx = withOperation(ctx, "ContactsApi.GetCustomFieldOptions")
+
 	uri := fmt.Sprintf("contacts/fields/%s/options", fieldId)
 
 	var result = new(FieldOptionsCollectionResponse)
 
-	err := contactsApi.client.Get(ctx, uri, result)
+	err := contactsApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
@@ -442,10 +551,12 @@ type AvailableField struct {
 	Options []string `json:"options,omitempty"`
 }
 
-func (contactsApi *ContactsApi) GetAvailableFields(ctx context.Context) ([]*AvailableField, error) {
+func (contactsApi *ContactsApi) GetAvailableFields(ctx context.Context, opts ...CallOption) ([]*AvailableField, error) {
+	ctx = withOperation(ctx, "ContactsApi.GetAvailableFields")
+
 	var result []*AvailableField
 
-	err := contactsApi.client.Get(ctx, "contacts/fields/available", &result)
+	err := contactsApi.client.Get(ctx, "contacts/fields/available", &result, opts...)
 
 	return result, err
 }
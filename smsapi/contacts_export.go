@@ -0,0 +1,243 @@
+package smsapi
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const DefaultExportWorkers = 4
+
+var ErrUnknownExportFormat = errors.New("smsapi: unknown export format")
+
+type ExportFormat string
+
+const (
+	ExportFormatCsv       = ExportFormat("csv")
+	ExportFormatJsonLines = ExportFormat("jsonl")
+)
+
+// ExportOptions configures ContactsApi.Export, nil means CSV fetched by DefaultExportWorkers
+// workers in pages of DefaultPageSize contacts.
+type ExportOptions struct {
+	Format ExportFormat
+	// Workers is the number of pages fetched concurrently.
+	Workers int
+	// PageSize is used when filters do not set a limit.
+	PageSize uint
+}
+
+var contactCsvHeader = []string{
+	"id",
+	"first_name",
+	"last_name",
+	"phone_number",
+	"email",
+	"gender",
+	"birthday_date",
+	"description",
+	"city",
+	"source",
+	"date_created",
+	"date_updated",
+}
+
+func contactCsvRecord(c *Contact) []string {
+	return []string{
+		c.Id,
+		c.FirstName,
+		c.LastName,
+		c.PhoneNumber,
+		c.Email,
+		c.Gender,
+		c.BirthdayDate,
+		c.Description,
+		c.City,
+		c.Source,
+		c.DateCreated,
+		c.DateUpdated,
+	}
+}
+
+type exportPage struct {
+	index    int
+	contacts []*Contact
+	err      error
+}
+
+// Export writes all contacts matching the filters to w, in the order returned by the API.
+// The first page is read to learn the collection size, the remaining pages are fetched
+// concurrently. Requests go through the client, so its RateLimiter and RetryPolicy apply.
+// It returns the number of exported contacts.
+func (contactsApi *ContactsApi) Export(ctx context.Context, w io.Writer, filters *ContactListFilters, options *ExportOptions, opts ...CallOption) (int, error) {
+	ctx = withOperation(ctx, "ContactsApi.Export")
+
+	o := options.withDefaults()
+
+	if o.Format != ExportFormatCsv && o.Format != ExportFormatJsonLines {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownExportFormat, o.Format)
+	}
+
+	f := ContactListFilters{}
+
+	if filters != nil {
+		f = *filters
+	}
+
+	if f.Limit == 0 {
+		f.Limit = o.PageSize
+	}
+
+	encoder := newContactEncoder(w, o.Format)
+
+	first, err := contactsApi.GetContacts(ctx, &f, opts...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err := encoder.write(first.Collection); err != nil {
+		return 0, err
+	}
+
+	written := len(first.Collection)
+
+	var offsets []uint
+
+	for offset := f.Offset + f.Limit; offset < first.Size; offset += f.Limit {
+		offsets = append(offsets, offset)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	defer close(jobs)
+
+	results := make(chan exportPage)
+
+	for n := 0; n < o.Workers; n++ {
+		go func() {
+			for index := range jobs {
+				page := f
+				page.Offset = offsets[index]
+
+				result, err := contactsApi.GetContacts(ctx, &page, opts...)
+
+				select {
+				case results <- exportPage{index: index, contacts: result.Collection, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	// Workers may run ahead of the writer only by a bounded number of pages.
+	window := o.Workers * 2
+	pending := map[int]exportPage{}
+	next, sent := 0, 0
+
+	for next < len(offsets) {
+		var dispatch chan int
+
+		if sent < len(offsets) && sent < next+window {
+			dispatch = jobs
+		}
+
+		select {
+		case dispatch <- sent:
+			sent++
+		case page := <-results:
+			if page.err != nil {
+				return written, page.err
+			}
+
+			pending[page.index] = page
+
+			for page, ok := pending[next]; ok; page, ok = pending[next] {
+				if err := encoder.write(page.contacts); err != nil {
+					return written, err
+				}
+
+				written += len(page.contacts)
+				delete(pending, next)
+				next++
+			}
+		case <-ctx.Done():
+			return written, ctx.Err()
+		}
+	}
+
+	return written, nil
+}
+
+func (o *ExportOptions) withDefaults() ExportOptions {
+	result := ExportOptions{}
+
+	if o != nil {
+		result = *o
+	}
+
+	if result.Format == "" {
+		result.Format = ExportFormatCsv
+	}
+
+	if result.Workers < 1 {
+		result.Workers = DefaultExportWorkers
+	}
+
+	if result.PageSize == 0 {
+		result.PageSize = DefaultPageSize
+	}
+
+	return result
+}
+
+type contactEncoder struct {
+	csv           *csv.Writer
+	json          *json.Encoder
+	headerWritten bool
+}
+
+func newContactEncoder(w io.Writer, format ExportFormat) *contactEncoder {
+	if format == ExportFormatJsonLines {
+		return &contactEncoder{json: json.NewEncoder(w)}
+	}
+
+	return &contactEncoder{csv: csv.NewWriter(w)}
+}
+
+// write encodes a page of contacts and flushes it to the underlying writer.
+func (e *contactEncoder) write(contacts []*Contact) error {
+	if e.json != nil {
+		for _, contact := range contacts {
+			if err := e.json.Encode(contact); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if !e.headerWritten {
+		e.headerWritten = true
+
+		if err := e.csv.Write(contactCsvHeader); err != nil {
+			return err
+		}
+	}
+
+	for _, contact := range contacts {
+		if err := e.csv.Write(contactCsvRecord(contact)); err != nil {
+			return err
+		}
+	}
+
+	e.csv.Flush()
+
+	return e.csv.Error()
+}
@@ -0,0 +1,145 @@
+package smsapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func serveContacts(mux *http.ServeMux, size int, requests *int32) {
+	mux.HandleFunc("/contacts", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		// Later pages answer faster to shuffle completion order.
+		time.Sleep(time.Duration(size-offset) * 100 * time.Microsecond)
+
+		var contacts []string
+
+		for i := offset; i < offset+limit && i < size; i++ {
+			contacts = append(contacts, fmt.Sprintf(`{"id":"%d","first_name":"Jon, %d","phone_number":"48100200%03d"}`, i, i, i))
+		}
+
+		fmt.Fprintf(w, `{"size":%d,"collection":[%s]}`, size, strings.Join(contacts, ","))
+	})
+}
+
+func TestContactsExportCsvPreservesOrder(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var requests int32
+
+	serveContacts(mux, 95, &requests)
+
+	out := new(bytes.Buffer)
+
+	filters := &ContactListFilters{PaginationFilters: PaginationFilters{Limit: 10}}
+
+	count, err := client.Contacts.Export(ctx, out, filters, &ExportOptions{Workers: 3})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+
+	if count != 95 || len(lines) != 96 || requests != 10 {
+		t.Fatalf("Expected 95 contacts in 10 requests, given: %d contacts %d lines %d requests", count, len(lines), requests)
+	}
+
+	if lines[0] != strings.Join(contactCsvHeader, ",") {
+		t.Errorf("Unexpected header: %s", lines[0])
+	}
+
+	for i, line := range lines[1:] {
+		if !strings.HasPrefix(line, fmt.Sprintf(`%d,"Jon, %d",`, i, i)) {
+			t.Fatalf("Unexpected line %d: %s", i, line)
+		}
+	}
+}
+
+func TestContactsExportJsonLines(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var requests int32
+
+	serveContacts(mux, 25, &requests)
+
+	out := new(bytes.Buffer)
+
+	count, err := client.Contacts.Export(ctx, out, &ContactListFilters{PaginationFilters: PaginationFilters{Limit: 10}}, &ExportOptions{Format: ExportFormatJsonLines})
+
+	if err != nil || count != 25 {
+		t.Fatalf("Expected 25 contacts, given: %d %v", count, err)
+	}
+
+	for i, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		contact := new(Contact)
+
+		if err := json.Unmarshal([]byte(line), contact); err != nil || contact.Id != strconv.Itoa(i) {
+			t.Fatalf("Unexpected line %d: %s", i, line)
+		}
+	}
+}
+
+func TestContactsExportSinglePage(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var requests int32
+
+	serveContacts(mux, 3, &requests)
+
+	count, err := client.Contacts.Export(ctx, new(bytes.Buffer), nil, nil)
+
+	if err != nil || count != 3 || requests != 1 {
+		t.Errorf("Expected 3 contacts in 1 request, given: %d %d %v", count, requests, err)
+	}
+}
+
+func TestContactsExportUnknownFormat(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var requests int32
+
+	serveContacts(mux, 3, &requests)
+
+	_, err := client.Contacts.Export(ctx, new(bytes.Buffer), nil, &ExportOptions{Format: "json"})
+
+	if !errors.Is(err, ErrUnknownExportFormat) || requests != 0 {
+		t.Errorf("Expected ErrUnknownExportFormat without requests, given: %v %d", err, requests)
+	}
+}
+
+func TestContactsExportStopsOnError(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/contacts", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "20" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":"forbidden","message":"Forbidden"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"size":100,"collection":[{"id":"1"}]}`)
+	})
+
+	_, err := client.Contacts.Export(ctx, new(bytes.Buffer), &ContactListFilters{PaginationFilters: PaginationFilters{Limit: 10}}, nil)
+
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected forbidden error, given: %v", err)
+	}
+}
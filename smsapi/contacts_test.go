@@ -51,6 +51,34 @@ func TestContactsPageIterator(t *testing.T) {
 	}
 }
 
+func TestContactsAll(t *testing.T) {
+	client, mux, teardown := setup()
+
+	defer teardown()
+
+	mux.HandleFunc("/contacts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, readFixture("contacts/list_contacts.json"))
+
+		assertRequestMethod(t, r, "GET")
+	})
+
+	var contacts []*Contact
+
+	for contact, err := range client.Contacts.All(ctx, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		contacts = append(contacts, contact)
+	}
+
+	expected := createExpectedContactsCollection().Collection
+
+	if !reflect.DeepEqual(contacts, expected) {
+		t.Errorf("Given: %+v Expected: %+v", contacts, expected)
+	}
+}
+
 func TestGetContact(t *testing.T) {
 	client, mux, teardown := setup()
 
@@ -1,26 +1,267 @@
 package smsapi
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 )
 
+// Sentinel errors matched by ErrorResponse through errors.Is:
+//
+//	if errors.Is(err, smsapi.ErrInsufficientPoints) { ... }
+var (
+	ErrUnauthorized       = errors.New("smsapi: unauthorized")
+	ErrForbidden          = errors.New("smsapi: action not allowed")
+	ErrInsufficientPoints = errors.New("smsapi: insufficient points")
+	ErrInvalidRecipient   = errors.New("smsapi: invalid recipient")
+	ErrSenderNotActive    = errors.New("smsapi: sender name not active")
+	ErrDuplicateIdx       = errors.New("smsapi: duplicated idx")
+	ErrInvalidRequest     = errors.New("smsapi: invalid request")
+	ErrRateLimited        = errors.New("smsapi: rate limited")
+	ErrNotFound           = errors.New("smsapi: not found")
+	ErrServer             = errors.New("smsapi: server error")
+)
+
+// Legacy API error codes as documented in the "error codes" section of SMSAPI docs.
+const (
+	ErrorCodeInvalidRequest       = 8
+	ErrorCodeMessageTooLong       = 11
+	ErrorCodeTooManyParts         = 12
+	ErrorCodeInvalidRecipients    = 13
+	ErrorCodeInvalidSender        = 14
+	ErrorCodeInvalidParameters    = 18
+	ErrorCodeTooManyMessages      = 19
+	ErrorCodeNoSenderAvailable    = 33
+	ErrorCodeGroupNotFound        = 40
+	ErrorCodeTooManyAttempts      = 52
+	ErrorCodeDuplicatedIdx        = 53
+	ErrorCodeBlacklisted          = 57
+	ErrorCodeOptedOut             = 59
+	ErrorCodeInvalidCharacters    = 76
+	ErrorCodeInvalidAuthorization = 101
+	ErrorCodeInvalidCredentials   = 102
+	ErrorCodeInsufficientPoints   = 103
+	ErrorCodeTemplateNotFound     = 104
+	ErrorCodeIpNotAllowed         = 105
+	ErrorCodeActionNotAllowed     = 110
+	ErrorCodeInternal             = 201
+	ErrorCodeTooManyConnections   = 202
+	ErrorCodeTooManyRequests      = 203
+	ErrorCodeMessageNotFound      = 301
+	ErrorCodeInternalSystem       = 998
+	ErrorCodeInternalSystemOther  = 999
+	ErrorCodeMainUserOnly         = 1000
+)
+
+var errorCodeKinds = map[int]error{
+	ErrorCodeInvalidRequest:       ErrInvalidRequest,
+	ErrorCodeMessageTooLong:       ErrInvalidRequest,
+	ErrorCodeTooManyParts:         ErrInvalidRequest,
+	ErrorCodeInvalidRecipients:    ErrInvalidRecipient,
+	ErrorCodeInvalidSender:        ErrSenderNotActive,
+	ErrorCodeInvalidParameters:    ErrInvalidRequest,
+	ErrorCodeTooManyMessages:      ErrInvalidRequest,
+	ErrorCodeNoSenderAvailable:    ErrSenderNotActive,
+	ErrorCodeGroupNotFound:        ErrNotFound,
+	ErrorCodeTooManyAttempts:      ErrRateLimited,
+	ErrorCodeDuplicatedIdx:        ErrDuplicateIdx,
+	ErrorCodeBlacklisted:          ErrInvalidRecipient,
+	ErrorCodeOptedOut:             ErrInvalidRecipient,
+	ErrorCodeInvalidCharacters:    ErrInvalidRequest,
+	ErrorCodeInvalidAuthorization: ErrUnauthorized,
+	ErrorCodeInvalidCredentials:   ErrUnauthorized,
+	ErrorCodeInsufficientPoints:   ErrInsufficientPoints,
+	ErrorCodeTemplateNotFound:     ErrNotFound,
+	ErrorCodeIpNotAllowed:         ErrUnauthorized,
+	ErrorCodeActionNotAllowed:     ErrForbidden,
+	ErrorCodeInternal:             ErrServer,
+	ErrorCodeTooManyConnections:   ErrRateLimited,
+	ErrorCodeTooManyRequests:      ErrRateLimited,
+	ErrorCodeMessageNotFound:      ErrNotFound,
+	ErrorCodeInternalSystem:       ErrServer,
+	ErrorCodeInternalSystemOther:  ErrServer,
+	ErrorCodeMainUserOnly:         ErrForbidden,
+}
+
+var statusKinds = map[int]error{
+	http.StatusBadRequest:          ErrInvalidRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusPaymentRequired:     ErrInsufficientPoints,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusUnprocessableEntity: ErrInvalidRequest,
+	http.StatusTooManyRequests:     ErrRateLimited,
+}
+
 type ErrorResponse struct {
-	Status         int
-	Code           int              `json:"error"`
+	Status int
+	// Code is the numeric error code returned by legacy (*.do) endpoints.
+	Code int `json:"error"`
+	// Key is the textual error identifier returned by REST endpoints, e.g. "invalid_request_data".
+	Key            string           `json:"-"`
 	Message        string           `json:"message"`
 	InvalidNumbers []*InvalidNumber `json:"invalid_numbers,omitempty"`
 }
 
+// UnmarshalJSON accepts `error` given either as legacy numeric code or REST string key.
+func (e *ErrorResponse) UnmarshalJSON(data []byte) error {
+	var payload struct {
+		Error          json.RawMessage  `json:"error"`
+		Message        string           `json:"message"`
+		InvalidNumbers []*InvalidNumber `json:"invalid_numbers,omitempty"`
+	}
+
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+
+	e.Message = payload.Message
+	e.InvalidNumbers = payload.InvalidNumbers
+
+	if len(payload.Error) == 0 || string(payload.Error) == "null" {
+		return nil
+	}
+
+	if payload.Error[0] == '"' {
+		return json.Unmarshal(payload.Error, &e.Key)
+	}
+
+	return json.Unmarshal(payload.Error, &e.Code)
+}
+
 type InvalidNumber struct {
 	Number          string `json:"number,omitempty"`
 	SubmittedNumber string `json:"submitted_number,omitempty"`
 	Message         string `json:"message,omitempty"`
 }
 
-func (error *ErrorResponse) Error() string {
+func (e *ErrorResponse) Error() string {
 	return fmt.Sprintf("Status: %d Code: %v Message: %s",
-		error.Status,
-		error.Code,
-		error.Message,
+		e.Status,
+		e.Code,
+		e.Message,
 	)
 }
+
+// Kind returns the sentinel error describing the failure or nil if it is not recognized.
+// The legacy API error code takes precedence over the HTTP status.
+func (e *ErrorResponse) Kind() error {
+	if kind, ok := errorCodeKinds[e.Code]; ok {
+		return kind
+	}
+
+	return statusKind(e.Status)
+}
+
+func statusKind(status int) error {
+	if kind, ok := statusKinds[status]; ok {
+		return kind
+	}
+
+	if status >= http.StatusInternalServerError {
+		return ErrServer
+	}
+
+	return nil
+}
+
+func (e *ErrorResponse) Is(target error) bool {
+	kind := e.Kind()
+
+	return kind != nil && kind == target
+}
+
+// Temporary reports a transient server side failure.
+func (e *ErrorResponse) Temporary() bool {
+	return e.Kind() == ErrServer
+}
+
+// Retryable reports whether repeating the same request later may succeed.
+func (e *ErrorResponse) Retryable() bool {
+	return e.Temporary() || e.Kind() == ErrRateLimited
+}
+
+// EmptyResponseError is returned when a failed response has no body at all.
+type EmptyResponseError struct {
+	Status int
+}
+
+func (e *EmptyResponseError) Error() string {
+	return fmt.Sprintf("smsapi: empty response, status: %d", e.Status)
+}
+
+func (e *EmptyResponseError) Is(target error) bool {
+	kind := statusKind(e.Status)
+
+	return kind != nil && kind == target
+}
+
+func (e *EmptyResponseError) Temporary() bool {
+	return statusKind(e.Status) == ErrServer
+}
+
+func (e *EmptyResponseError) Retryable() bool {
+	return e.Temporary() || statusKind(e.Status) == ErrRateLimited
+}
+
+// UnexpectedResponseError is returned when a response body is not JSON, typically an
+// HTML page produced by a gateway or proxy in front of the API.
+type UnexpectedResponseError struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+const unexpectedBodySnippetLength = 128
+
+func newUnexpectedResponseError(r *http.Response, body []byte) *UnexpectedResponseError {
+	return &UnexpectedResponseError{
+		Status:      r.StatusCode,
+		ContentType: r.Header.Get("Content-Type"),
+		Body:        body,
+	}
+}
+
+func (e *UnexpectedResponseError) Error() string {
+	snippet := strings.TrimSpace(string(e.Body))
+
+	if len(snippet) > unexpectedBodySnippetLength {
+		snippet = snippet[:unexpectedBodySnippetLength] + "..."
+	}
+
+	return fmt.Sprintf("smsapi: unexpected response, status: %d content type: %s body: %s", e.Status, e.ContentType, snippet)
+}
+
+func (e *UnexpectedResponseError) Is(target error) bool {
+	kind := statusKind(e.Status)
+
+	return kind != nil && kind == target
+}
+
+func (e *UnexpectedResponseError) Temporary() bool {
+	return statusKind(e.Status) == ErrServer
+}
+
+func (e *UnexpectedResponseError) Retryable() bool {
+	return e.Temporary() || statusKind(e.Status) == ErrRateLimited
+}
+
+// IsRetryable reports whether err, or any error it wraps, is retryable.
+func IsRetryable(err error) bool {
+	var retryable interface {
+		Retryable() bool
+	}
+
+	return errors.As(err, &retryable) && retryable.Retryable()
+}
+
+// IsTemporary reports whether err, or any error it wraps, is temporary.
+func IsTemporary(err error) bool {
+	var temporary interface {
+		Temporary() bool
+	}
+
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
@@ -0,0 +1,102 @@
+package smsapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestErrorResponseIs(t *testing.T) {
+	tests := []struct {
+		err      *ErrorResponse
+		expected error
+	}{
+		{&ErrorResponse{Status: 200, Code: 13}, ErrInvalidRecipient},
+		{&ErrorResponse{Status: 200, Code: 57}, ErrInvalidRecipient},
+		{&ErrorResponse{Status: 200, Code: 103}, ErrInsufficientPoints},
+		{&ErrorResponse{Status: 200, Code: 101}, ErrUnauthorized},
+		{&ErrorResponse{Status: 200, Code: 14}, ErrSenderNotActive},
+		{&ErrorResponse{Status: 200, Code: 53}, ErrDuplicateIdx},
+		{&ErrorResponse{Status: 200, Code: 203}, ErrRateLimited},
+		{&ErrorResponse{Status: 200, Code: 999}, ErrServer},
+		{&ErrorResponse{Status: 401}, ErrUnauthorized},
+		{&ErrorResponse{Status: 404}, ErrNotFound},
+		{&ErrorResponse{Status: 429}, ErrRateLimited},
+		{&ErrorResponse{Status: 503}, ErrServer},
+		{&ErrorResponse{Status: 404, Code: 103}, ErrInsufficientPoints},
+	}
+
+	for _, test := range tests {
+		if !errors.Is(test.err, test.expected) {
+			t.Errorf("%v expected to be: %v", test.err, test.expected)
+		}
+
+		if errors.Is(test.err, ErrForbidden) {
+			t.Errorf("%v is not expected to be forbidden", test.err)
+		}
+	}
+
+	if (&ErrorResponse{Status: 200, Code: 12345}).Kind() != nil {
+		t.Error("Expected unknown code to have no kind")
+	}
+}
+
+func TestErrorResponseAs(t *testing.T) {
+	err := fmt.Errorf("sending: %w", &ErrorResponse{Status: 200, Code: 13, Message: "No correct phone numbers"})
+
+	var errorResponse *ErrorResponse
+
+	if !errors.As(err, &errorResponse) || errorResponse.Code != 13 {
+		t.Errorf("Expected ErrorResponse, given: %v", err)
+	}
+
+	if !errors.Is(err, ErrInvalidRecipient) {
+		t.Errorf("Expected wrapped error to be invalid recipient")
+	}
+}
+
+func TestErrorResponseRetryable(t *testing.T) {
+	tests := []struct {
+		err       *ErrorResponse
+		temporary bool
+		retryable bool
+	}{
+		{&ErrorResponse{Status: 500}, true, true},
+		{&ErrorResponse{Status: 200, Code: 201}, true, true},
+		{&ErrorResponse{Status: 429}, false, true},
+		{&ErrorResponse{Status: 200, Code: 202}, false, true},
+		{&ErrorResponse{Status: 200, Code: 13}, false, false},
+		{&ErrorResponse{Status: 401}, false, false},
+	}
+
+	for _, test := range tests {
+		if test.err.Temporary() != test.temporary || IsTemporary(test.err) != test.temporary {
+			t.Errorf("%v temporary expected: %v", test.err, test.temporary)
+		}
+
+		if test.err.Retryable() != test.retryable || IsRetryable(fmt.Errorf("%w", test.err)) != test.retryable {
+			t.Errorf("%v retryable expected: %v", test.err, test.retryable)
+		}
+	}
+
+	if IsRetryable(errors.New("other")) {
+		t.Error("Expected plain error not to be retryable")
+	}
+}
+
+func TestClientErrorKind(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"Authorization failed","error":"authorization_failed"}`)
+	})
+
+	_, err := client.Profile.Details(ctx)
+
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected unauthorized, given: %v", err)
+	}
+}
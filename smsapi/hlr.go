@@ -17,14 +17,16 @@ type Hlr struct {
 	PhoneNumber string `json:"number"`
 }
 
-func (hlrApi *HlrApi) CheckNumber(ctx context.Context, phonenumber string) (*HlrResponse, error) {
+func (hlrApi *HlrApi) CheckNumber(ctx context.Context, phonenumber string, opts ...CallOption) (*HlrResponse, error) {
+	ctx = withOperation(ctx, "HlrApi.CheckNumber")
+
 	var result = new(HlrResponse)
 
 	payload := Hlr{
 		PhoneNumber: phonenumber,
 	}
 
-	err := hlrApi.client.LegacyPost(ctx, "/hlr.do", result, payload)
+	err := hlrApi.client.LegacyPost(ctx, "/hlr.do", result, payload, opts...)
 
 	return result, err
 }
@@ -0,0 +1,153 @@
+// Package callstats extracts outcome and usage of an API call from its buffered
+// response, shared by the instrumentation packages.
+package callstats
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/smsapi/smsapi-go/smsapi"
+)
+
+const ErrorCodeTransport = "transport"
+
+type Result struct {
+	Failed bool
+	// ErrorCode is the legacy numeric code, the REST error key, the HTTP status
+	// or ErrorCodeTransport, in that order of preference.
+	ErrorCode string
+	Message   string
+
+	// Send is set for successful legacy sends and HLR lookups, see IsSend. Messages,
+	// Parts and Points are reported for them only.
+	Send     bool
+	Messages int
+	Parts    int
+	Points   float64
+}
+
+// Inspect reads the buffered response body without consuming it.
+func Inspect(req *http.Request, resp *http.Response, err error, area smsapi.ApiArea) Result {
+	if err != nil {
+		return Result{Failed: true, ErrorCode: ErrorCodeTransport, Message: err.Error()}
+	}
+
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if readErr != nil {
+		return Result{Failed: true, ErrorCode: ErrorCodeTransport, Message: readErr.Error()}
+	}
+
+	var errorResponse smsapi.ErrorResponse
+
+	json.Unmarshal(body, &errorResponse)
+
+	success := resp.StatusCode >= 200 && resp.StatusCode < 300
+
+	if !success || errorResponse.Code != 0 {
+		result := Result{Failed: true, ErrorCode: strconv.Itoa(resp.StatusCode), Message: errorResponse.Message}
+
+		switch {
+		case errorResponse.Code != 0:
+			result.ErrorCode = strconv.Itoa(errorResponse.Code)
+		case errorResponse.Key != "":
+			result.ErrorCode = errorResponse.Key
+		}
+
+		if result.Message == "" {
+			result.Message = http.StatusText(resp.StatusCode)
+		}
+
+		return result
+	}
+
+	if IsSend(req, area) {
+		result := usage(body)
+		result.Send = true
+
+		return result
+	}
+
+	return Result{}
+}
+
+// IsSend reports whether the request sends messages or looks up numbers, spending points.
+// Status lookups (GET) and removals of scheduled messages (sch_del) of the same endpoints
+// return message details too, but spend nothing.
+func IsSend(req *http.Request, area smsapi.ApiArea) bool {
+	switch area {
+	case smsapi.AreaSms, smsapi.AreaMms, smsapi.AreaVms, smsapi.AreaHlr:
+	default:
+		return false
+	}
+
+	if req.Method != http.MethodPost {
+		return false
+	}
+
+	if req.GetBody == nil {
+		return true
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		return true
+	}
+
+	defer body.Close()
+
+	var payload map[string]json.RawMessage
+
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return true
+	}
+
+	_, removal := payload["sch_del"]
+
+	return !removal
+}
+
+type usageItem struct {
+	Id     string        `json:"id"`
+	Points smsapi.Points `json:"points"`
+	// Price is reported instead of points by HLR lookups.
+	Price smsapi.Points `json:"price"`
+	Parts int           `json:"parts"`
+}
+
+func (item usageItem) points() float64 {
+	return float64(item.Points) + float64(item.Price)
+}
+
+// usage sums points and parts of a legacy send or HLR response, given either as a
+// single item or as a {"list": [...]} collection.
+func usage(body []byte) Result {
+	var payload struct {
+		usageItem
+		List []usageItem `json:"list"`
+	}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return Result{}
+	}
+
+	result := Result{Points: payload.points(), Parts: payload.Parts}
+
+	if payload.Id != "" {
+		result.Messages = 1
+	}
+
+	for _, item := range payload.List {
+		result.Messages++
+		result.Points += item.points()
+		result.Parts += item.Parts
+	}
+
+	return result
+}
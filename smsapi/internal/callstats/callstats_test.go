@@ -0,0 +1,64 @@
+package callstats
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/smsapi/smsapi-go/smsapi"
+)
+
+func response(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func request(method, body string) *http.Request {
+	req, _ := http.NewRequest(method, "https://api.smsapi.pl/sms.do", strings.NewReader(body))
+
+	return req
+}
+
+func TestInspect(t *testing.T) {
+	send := request(http.MethodPost, `{"to":"48100200300","message":"test"}`)
+
+	tests := []struct {
+		name     string
+		req      *http.Request
+		resp     *http.Response
+		err      error
+		area     smsapi.ApiArea
+		expected Result
+	}{
+		{"transport", send, nil, errors.New("reset"), smsapi.AreaSms, Result{Failed: true, ErrorCode: ErrorCodeTransport, Message: "reset"}},
+		{"legacy code", send, response(200, `{"error":103,"message":"Not enough points"}`), nil, smsapi.AreaSms, Result{Failed: true, ErrorCode: "103", Message: "Not enough points"}},
+		{"rest key", send, response(400, `{"error":"invalid_request_data","message":"Invalid"}`), nil, smsapi.AreaContacts, Result{Failed: true, ErrorCode: "invalid_request_data", Message: "Invalid"}},
+		{"status only", send, response(502, ``), nil, smsapi.AreaSms, Result{Failed: true, ErrorCode: "502", Message: "Bad Gateway"}},
+		{"sms list", send, response(200, `{"count":2,"list":[{"id":"1","points":"0.25","parts":2},{"id":"2","points":0.25,"parts":1}]}`), nil, smsapi.AreaSms, Result{Send: true, Messages: 2, Parts: 3, Points: 0.5}},
+		{"hlr", send, response(200, `{"id":"1","price":0.5}`), nil, smsapi.AreaHlr, Result{Send: true, Messages: 1, Points: 0.5}},
+		{"status lookup", request(http.MethodGet, ""), response(200, `{"count":1,"list":[{"id":"1","points":0.16,"parts":1}]}`), nil, smsapi.AreaSms, Result{}},
+		{"scheduled removal", request(http.MethodPost, `{"sch_del":"1"}`), response(200, `{"count":1,"list":[{"id":"1"}]}`), nil, smsapi.AreaSms, Result{}},
+		{"profile points are balance", send, response(200, `{"points":100}`), nil, smsapi.AreaProfile, Result{}},
+	}
+
+	for _, test := range tests {
+		given := Inspect(test.req, test.resp, test.err, test.area)
+
+		if given != test.expected {
+			t.Errorf("%s expected: %+v given: %+v", test.name, test.expected, given)
+		}
+	}
+}
+
+func TestInspectPreservesBody(t *testing.T) {
+	resp := response(200, `{"count":1}`)
+
+	Inspect(request(http.MethodPost, ""), resp, nil, smsapi.AreaSms)
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if string(body) != `{"count":1}` {
+		t.Errorf("Expected body to be restored, given: %s", body)
+	}
+}
@@ -0,0 +1,315 @@
+package smsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// LogOptions controls redaction of logged data. The zero value redacts the bearer
+// token and message contents, and masks phone numbers.
+type LogOptions struct {
+	RevealToken        bool
+	RevealPhoneNumbers bool
+	RevealMessages     bool
+}
+
+var phoneNumberKeys = map[string]bool{
+	"to":               true,
+	"number":           true,
+	"phone_number":     true,
+	"phoneNumber":      true,
+	"submitted_number": true,
+	"receiver_number":  true,
+}
+
+var messageKeys = map[string]bool{
+	"message": true,
+	"content": true,
+	"code":    true,
+	"tts":     true,
+	"smil":    true,
+	"subject": true,
+	"param1":  true,
+	"param2":  true,
+	"param3":  true,
+	"param4":  true,
+}
+
+// WithLogger enables logging of every API call: method, path, area, status, latency
+// and error code. Request and response dumps are logged at debug level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) error {
+		o.logger = logger
+
+		return nil
+	}
+}
+
+// WithLogOptions configures redaction of logged data, see LogOptions.
+func WithLogOptions(logOptions LogOptions) Option {
+	return func(o *options) error {
+		o.logOptions = logOptions
+
+		return nil
+	}
+}
+
+// requestBodyForLog returns a copy of the request body when it is going to be dumped.
+func (client *Client) requestBodyForLog(req *http.Request) []byte {
+	if client.Logger == nil || req.GetBody == nil || !client.Logger.Enabled(req.Context(), slog.LevelDebug) {
+		return nil
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		return nil
+	}
+
+	defer body.Close()
+
+	data, _ := io.ReadAll(body)
+
+	return data
+}
+
+func (client *Client) logCall(req *http.Request, requestBody []byte, meta *Response, err error) {
+	if client.Logger == nil {
+		return
+	}
+
+	ctx := req.Context()
+	info, _ := RequestInfoFromContext(ctx)
+
+	attrs := callAttrs(info, meta.StatusCode, meta.Latency)
+	attrs = append(attrs, slog.Int("attempts", meta.Attempts))
+
+	if meta.RequestId != "" {
+		attrs = append(attrs, slog.String("request_id", meta.RequestId))
+	}
+
+	level := slog.LevelInfo
+
+	if err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, client.LogOptions.errorAttrs(err)...)
+	}
+
+	client.Logger.LogAttrs(ctx, level, "smsapi request", attrs...)
+
+	if !client.Logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	o := client.LogOptions
+
+	client.Logger.LogAttrs(ctx, slog.LevelDebug, "smsapi request dump",
+		slog.String("url", o.redactUrl(req.URL)),
+		slog.String("authorization", o.redactAuthorization(req.Header.Get("Authorization"))),
+		slog.String("request_body", o.redactBody(requestBody, req.Header.Get("Content-Type"))),
+		slog.String("response_body", o.redactBody(meta.Body, meta.Header.Get("Content-Type"))),
+	)
+}
+
+func callAttrs(info *RequestInfo, status int, latency time.Duration) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("method", info.Method),
+		slog.String("path", info.Path),
+		slog.String("area", string(info.Area)),
+		slog.Duration("latency", latency),
+	}
+
+	if status != 0 {
+		attrs = append(attrs, slog.Int("status", status))
+	}
+
+	return attrs
+}
+
+// errorAttrs describes a failed call. Error messages may quote recipients and message
+// contents, so they are redacted unless both are revealed.
+func (o LogOptions) errorAttrs(err error) []slog.Attr {
+	attrs := []slog.Attr{slog.String("error", o.redactError(err))}
+
+	var errorResponse *ErrorResponse
+
+	if errors.As(err, &errorResponse) {
+		if errorResponse.Code != 0 {
+			attrs = append(attrs, slog.Int("error_code", errorResponse.Code))
+		}
+
+		if errorResponse.Key != "" {
+			attrs = append(attrs, slog.String("error_key", errorResponse.Key))
+		}
+
+		if errorResponse.Status != 0 {
+			attrs = append(attrs, slog.Int("error_status", errorResponse.Status))
+		}
+	}
+
+	return attrs
+}
+
+func (o LogOptions) redactError(err error) string {
+	if o.RevealPhoneNumbers && o.RevealMessages {
+		return err.Error()
+	}
+
+	var errorResponse *ErrorResponse
+	var unexpectedResponse *UnexpectedResponseError
+	var invalidPhoneNumber *InvalidPhoneNumberError
+	var urlError *url.Error
+
+	switch {
+	case errors.As(err, &errorResponse):
+		e := *errorResponse
+		e.Message = redacted
+
+		return e.Error()
+	case errors.As(err, &unexpectedResponse):
+		e := *unexpectedResponse
+		e.Body = []byte(redacted)
+
+		return e.Error()
+	case errors.As(err, &invalidPhoneNumber):
+		e := *invalidPhoneNumber
+		e.PhoneNumber = maskPhoneNumbers(e.PhoneNumber)
+
+		return e.Error()
+	case errors.As(err, &urlError):
+		e := *urlError
+
+		if u, parseErr := url.Parse(e.URL); parseErr == nil {
+			e.URL = o.redactUrl(u)
+		} else {
+			e.URL = redacted
+		}
+
+		return e.Error()
+	}
+
+	return err.Error()
+}
+
+func (o LogOptions) redactAuthorization(authorization string) string {
+	if o.RevealToken || authorization == "" {
+		return authorization
+	}
+
+	if i := strings.IndexByte(authorization, ' '); i > 0 {
+		return authorization[:i+1] + redacted
+	}
+
+	return redacted
+}
+
+func (o LogOptions) redactUrl(u *url.URL) string {
+	c := *u
+	c.RawQuery = o.redactValues(u.Query()).Encode()
+
+	return c.String()
+}
+
+func (o LogOptions) redactBody(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	if strings.HasPrefix(contentType, string(ContentTypeXFormUrlencoded)) {
+		values, err := url.ParseQuery(string(body))
+
+		if err == nil {
+			return o.redactValues(values).Encode()
+		}
+	}
+
+	var payload interface{}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		if o.RevealPhoneNumbers && o.RevealMessages {
+			return string(body)
+		}
+
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+
+	redactedBody, _ := json.Marshal(o.redactJson("", payload))
+
+	return string(redactedBody)
+}
+
+func (o LogOptions) redactValues(values url.Values) url.Values {
+	result := url.Values{}
+
+	for key, vs := range values {
+		for _, v := range vs {
+			result.Add(key, o.redactString(key, v))
+		}
+	}
+
+	return result
+}
+
+func (o LogOptions) redactJson(key string, v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = o.redactJson(k, item)
+		}
+
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = o.redactJson(key, item)
+		}
+
+		return value
+	case string:
+		return o.redactString(key, value)
+	case float64:
+		if phoneNumberKeys[key] && !o.RevealPhoneNumbers {
+			return maskPhoneNumbers(fmt.Sprintf("%.0f", value))
+		}
+	}
+
+	return v
+}
+
+func (o LogOptions) redactString(key, value string) string {
+	if phoneNumberKeys[key] && !o.RevealPhoneNumbers {
+		return maskPhoneNumbers(value)
+	}
+
+	if messageKeys[key] && !o.RevealMessages && value != "" {
+		return redacted
+	}
+
+	return value
+}
+
+// maskPhoneNumbers keeps only the last 3 digits of every comma separated number.
+func maskPhoneNumbers(numbers string) string {
+	parts := strings.Split(numbers, ",")
+
+	for i, number := range parts {
+		number = strings.TrimSpace(number)
+
+		if len(number) <= 3 {
+			parts[i] = number
+			continue
+		}
+
+		parts[i] = strings.Repeat("*", len(number)-3) + number[len(number)-3:]
+	}
+
+	return strings.Join(parts, ",")
+}
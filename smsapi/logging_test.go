@@ -0,0 +1,182 @@
+package smsapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func setupLogging(level slog.Level) (*Client, *http.ServeMux, *bytes.Buffer, func()) {
+	client, mux, teardown := setup()
+
+	out := new(bytes.Buffer)
+
+	client.Auth.AccessToken = "secret-token"
+	client.Logger = slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: level}))
+
+	return client, mux, out, teardown
+}
+
+func logRecords(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		record := map[string]interface{}{}
+
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestLoggingSuccessfulCall(t *testing.T) {
+	client, mux, out, teardown := setupLogging(slog.LevelInfo)
+	defer teardown()
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"username":"test"}`)
+	})
+
+	client.Profile.Details(ctx)
+
+	records := logRecords(t, out)
+
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, given: %v", records)
+	}
+
+	r := records[0]
+
+	if r["level"] != "INFO" || r["method"] != http.MethodGet || r["path"] != "/profile" || r["area"] != string(AreaProfile) || r["status"] != float64(200) {
+		t.Errorf("Unexpected record: %v", r)
+	}
+}
+
+func TestLoggingFailedCall(t *testing.T) {
+	client, mux, out, teardown := setupLogging(slog.LevelInfo)
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"error":103,"message":"Not enough points"}`)
+	})
+
+	client.Sms.Send(ctx, "48100200300", "secret message", "")
+
+	r := logRecords(t, out)[0]
+
+	if r["level"] != "ERROR" || r["error_code"] != float64(ErrorCodeInsufficientPoints) {
+		t.Errorf("Unexpected record: %v", r)
+	}
+}
+
+func TestLoggingErrorIsRedacted(t *testing.T) {
+	client, mux, out, teardown := setupLogging(slog.LevelInfo)
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"error":13,"message":"Invalid recipient 48100200300"}`)
+	})
+
+	client.Sms.Send(ctx, "48100200300", "secret message", "")
+
+	r := logRecords(t, out)[0]
+
+	if strings.Contains(out.String(), "48100200300") || r["error_code"] != float64(13) {
+		t.Errorf("Unexpected record: %v", r)
+	}
+
+	out.Reset()
+	client.LogOptions = LogOptions{RevealPhoneNumbers: true, RevealMessages: true}
+
+	client.Sms.Send(ctx, "48100200300", "secret message", "")
+
+	if !strings.Contains(logRecords(t, out)[0]["error"].(string), "Invalid recipient 48100200300") {
+		t.Errorf("Expected revealed error message, given: %s", out)
+	}
+}
+
+func TestLoggingDebugDumpIsRedacted(t *testing.T) {
+	client, mux, out, teardown := setupLogging(slog.LevelDebug)
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, readFixture("sms/collection.json"))
+	})
+
+	client.Sms.Send(ctx, "48100200300", "secret message", "")
+
+	dump := out.String()
+
+	for _, leaked := range []string{"secret-token", "48100200300", "secret message"} {
+		if strings.Contains(dump, leaked) {
+			t.Errorf("Log contains %q: %s", leaked, dump)
+		}
+	}
+
+	if !strings.Contains(dump, "********300") || !strings.Contains(dump, "Bearer [REDACTED]") {
+		t.Errorf("Expected masked values, given: %s", dump)
+	}
+}
+
+func TestLoggingRevealOptions(t *testing.T) {
+	client, mux, out, teardown := setupLogging(slog.LevelDebug)
+	defer teardown()
+
+	client.LogOptions = LogOptions{RevealToken: true, RevealPhoneNumbers: true, RevealMessages: true}
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, readFixture("sms/collection.json"))
+	})
+
+	client.Sms.Send(ctx, "48100200300", "secret message", "")
+
+	dump := out.String()
+
+	for _, value := range []string{"secret-token", "48100200300", "secret message"} {
+		if !strings.Contains(dump, value) {
+			t.Errorf("Log does not contain %q: %s", value, dump)
+		}
+	}
+}
+
+func TestLogOptionsRedactValues(t *testing.T) {
+	values := url.Values{
+		"to":           {"48100200300,48500600700"},
+		"phone_number": {"48100200300"},
+		"message":      {"hello"},
+		"from":         {"Test"},
+	}
+
+	given := LogOptions{}.redactValues(values)
+
+	expected := url.Values{
+		"to":           {"********300,********700"},
+		"phone_number": {"********300"},
+		"message":      {redacted},
+		"from":         {"Test"},
+	}
+
+	if given.Encode() != expected.Encode() {
+		t.Errorf("Expected: %v given: %v", expected, given)
+	}
+}
+
+func TestLogOptionsRedactJsonBody(t *testing.T) {
+	body := []byte(`{"phone_number":"48100200300","list":[{"number":48500600700,"content":"hi"}]}`)
+
+	given := LogOptions{}.redactBody(body, string(ContentTypeJson))
+	expected := `{"list":[{"content":"[REDACTED]","number":"********700"}],"phone_number":"********300"}`
+
+	if given != expected {
+		t.Errorf("Expected: %s given: %s", expected, given)
+	}
+}
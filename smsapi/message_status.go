@@ -0,0 +1,133 @@
+package smsapi
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// MessageStatus is the delivery status of an SMS, MMS or VMS. Values not known to the
+// library are kept as received, so they survive decoding and encoding unchanged.
+type MessageStatus string
+
+const (
+	MessageStatusNotFound    = MessageStatus("NOT_FOUND")
+	MessageStatusExpired     = MessageStatus("EXPIRED")
+	MessageStatusSent        = MessageStatus("SENT")
+	MessageStatusDelivered   = MessageStatus("DELIVERED")
+	MessageStatusUndelivered = MessageStatus("UNDELIVERED")
+	MessageStatusFailed      = MessageStatus("FAILED")
+	MessageStatusRejected    = MessageStatus("REJECTED")
+	MessageStatusUnknown     = MessageStatus("UNKNOWN")
+	MessageStatusQueue       = MessageStatus("QUEUE")
+	MessageStatusAccepted    = MessageStatus("ACCEPTED")
+	MessageStatusRenewal     = MessageStatus("RENEWAL")
+	MessageStatusStop        = MessageStatus("STOP")
+)
+
+// messageStatusCodes are numeric status codes sent in delivery report callbacks.
+var messageStatusCodes = map[MessageStatus]int{
+	MessageStatusNotFound:    401,
+	MessageStatusExpired:     402,
+	MessageStatusSent:        403,
+	MessageStatusDelivered:   404,
+	MessageStatusUndelivered: 405,
+	MessageStatusFailed:      406,
+	MessageStatusRejected:    407,
+	MessageStatusUnknown:     408,
+	MessageStatusQueue:       409,
+	MessageStatusAccepted:    410,
+	MessageStatusRenewal:     411,
+	MessageStatusStop:        412,
+}
+
+var messageStatusesByCode = func() map[int]MessageStatus {
+	result := map[int]MessageStatus{}
+
+	for status, code := range messageStatusCodes {
+		result[code] = status
+	}
+
+	return result
+}()
+
+// ParseMessageStatus parses a status name, in any case, or a numeric status code.
+// Unknown values are returned unchanged.
+func ParseMessageStatus(value string) MessageStatus {
+	value = strings.TrimSpace(value)
+
+	if code, err := strconv.Atoi(value); err == nil {
+		if status, ok := messageStatusesByCode[code]; ok {
+			return status
+		}
+
+		return MessageStatus(value)
+	}
+
+	if status := MessageStatus(strings.ToUpper(value)); status.IsKnown() {
+		return status
+	}
+
+	return MessageStatus(value)
+}
+
+// MessageStatusFromCode returns the status of a numeric status code.
+func MessageStatusFromCode(code int) (MessageStatus, bool) {
+	status, ok := messageStatusesByCode[code]
+
+	return status, ok
+}
+
+// Code returns the numeric status code, 0 for unknown statuses.
+func (s MessageStatus) Code() int {
+	return messageStatusCodes[s]
+}
+
+func (s MessageStatus) IsKnown() bool {
+	_, ok := messageStatusCodes[s]
+
+	return ok
+}
+
+// IsFinal reports whether the status will not change anymore. UNKNOWN is final,
+// as it means no delivery report was received from the network.
+func (s MessageStatus) IsFinal() bool {
+	return s.IsSuccess() || s.IsFailure() || s == MessageStatusUnknown
+}
+
+// IsSuccess reports whether the message was delivered.
+func (s MessageStatus) IsSuccess() bool {
+	return s == MessageStatusDelivered
+}
+
+// IsFailure reports whether the message will not be delivered.
+func (s MessageStatus) IsFailure() bool {
+	switch s {
+	case MessageStatusNotFound, MessageStatusExpired, MessageStatusUndelivered, MessageStatusFailed,
+		MessageStatusRejected, MessageStatusStop:
+		return true
+	}
+
+	return false
+}
+
+func (s MessageStatus) String() string {
+	return string(s)
+}
+
+// UnmarshalJSON accepts status names and numeric codes, encoded as JSON strings or numbers.
+func (s *MessageStatus) UnmarshalJSON(data []byte) error {
+	var value string
+
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	} else if string(data) != "null" {
+		value = string(data)
+	}
+
+	*s = ParseMessageStatus(value)
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package smsapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMessageStatus(t *testing.T) {
+	tests := map[string]MessageStatus{
+		"DELIVERED": MessageStatusDelivered,
+		"queue":     MessageStatusQueue,
+		"404":       MessageStatusDelivered,
+		"412":       MessageStatusStop,
+		"999":       MessageStatus("999"),
+		"NEW_ONE":   MessageStatus("NEW_ONE"),
+	}
+
+	for value, expected := range tests {
+		if given := ParseMessageStatus(value); given != expected {
+			t.Errorf("%s expected: %s given: %s", value, expected, given)
+		}
+	}
+}
+
+func TestMessageStatusPredicates(t *testing.T) {
+	tests := []struct {
+		status  MessageStatus
+		final   bool
+		success bool
+		failure bool
+	}{
+		{MessageStatusDelivered, true, true, false},
+		{MessageStatusUndelivered, true, false, true},
+		{MessageStatusExpired, true, false, true},
+		{MessageStatusUnknown, true, false, false},
+		{MessageStatusQueue, false, false, false},
+		{MessageStatusSent, false, false, false},
+		{MessageStatus("NEW_ONE"), false, false, false},
+	}
+
+	for _, test := range tests {
+		if test.status.IsFinal() != test.final || test.status.IsSuccess() != test.success || test.status.IsFailure() != test.failure {
+			t.Errorf("Unexpected predicates of %s", test.status)
+		}
+	}
+
+	if MessageStatusDelivered.Code() != 404 || MessageStatus("NEW_ONE").Code() != 0 {
+		t.Error("Unexpected status codes")
+	}
+}
+
+func TestMessageStatusJson(t *testing.T) {
+	var given []*SmsResponse
+
+	err := json.Unmarshal([]byte(`[{"status":"DELIVERED"},{"status":405},{"status":"NEW_ONE"}]`), &given)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if given[0].Status != MessageStatusDelivered || given[1].Status != MessageStatusUndelivered || given[2].Status != "NEW_ONE" {
+		t.Errorf("Unexpected statuses: %s %s %s", given[0].Status, given[1].Status, given[2].Status)
+	}
+
+	encoded, _ := json.Marshal(given[2])
+
+	if string(encoded) != `{"status":"NEW_ONE"}` {
+		t.Errorf("Expected unknown status to round-trip, given: %s", encoded)
+	}
+}
@@ -29,14 +29,29 @@ type VerifyMfaCode struct {
 }
 
 // CreateCode generates a new MFA code and sends it to the given phone number.
-func (api *MfaApi) CreateCode(ctx context.Context, req *CreateMfaCode) (*MfaCode, error) {
+func (api *MfaApi) CreateCode(ctx context.Context, req *CreateMfaCode, opts ...CallOption) (*MfaCode, error) {
+	ctx = withOperation(ctx, "MfaApi.CreateCode")
 	result := new(MfaCode)
-	err := api.client.Post(ctx, "/mfa/codes", result, req)
+	phoneNumber, err := api.client.normalizePhoneNumber(req.PhoneNumber)
+	if err != nil {
+		return result, err
+	}
+	if phoneNumber != req.PhoneNumber {
+		normalized := *req
+		normalized.PhoneNumber = phoneNumber
+		req = &normalized
+	}
+	err = api.client.Post(ctx, "/mfa/codes", result, req, opts...)
 	return result, err
 }
 
 // VerifyCode verifies the MFA code for the given phone number.
-func (api *MfaApi) VerifyCode(ctx context.Context, phoneNumber, code string) error {
+func (api *MfaApi) VerifyCode(ctx context.Context, phoneNumber, code string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "MfaApi.VerifyCode")
+	phoneNumber, err := api.client.normalizePhoneNumber(phoneNumber)
+	if err != nil {
+		return err
+	}
 	body := &VerifyMfaCode{Code: code, PhoneNumber: phoneNumber}
-	return api.client.Urlencoded(ctx, http.MethodPost, "/mfa/codes/verifications", nil, body)
+	return api.client.Urlencoded(ctx, http.MethodPost, "/mfa/codes/verifications", nil, body, opts...)
 }
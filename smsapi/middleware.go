@@ -0,0 +1,253 @@
+package smsapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const DefaultRequestIdHeader = "X-Request-Id"
+
+// RoundTripFunc executes a single API call. The request context carries
+// RequestInfo describing the call.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps every API call made by Client, including all retries of it.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// ApiArea identifies the SMSAPI service a request is sent to.
+type ApiArea string
+
+const (
+	AreaSms          = ApiArea("sms")
+	AreaMms          = ApiArea("mms")
+	AreaVms          = ApiArea("vms")
+	AreaHlr          = ApiArea("hlr")
+	AreaContacts     = ApiArea("contacts")
+	AreaBlacklist    = ApiArea("blacklist")
+	AreaCallbacks    = ApiArea("callbacks")
+	AreaMfa          = ApiArea("mfa")
+	AreaOptOuts      = ApiArea("opt_outs")
+	AreaProfile      = ApiArea("profile")
+	AreaSubusers     = ApiArea("subusers")
+	AreaShortUrl     = ApiArea("short_url")
+	AreaSendernames  = ApiArea("sendernames")
+	AreaSmsTemplates = ApiArea("sms_templates")
+	AreaShipment     = ApiArea("shipment")
+	AreaUnknown      = ApiArea("unknown")
+)
+
+// areaPrefixes maps path prefixes to areas, more specific prefixes go first.
+var areaPrefixes = []struct {
+	prefix string
+	area   ApiArea
+}{
+	{"/sms.do", AreaSms},
+	{"/mms.do", AreaMms},
+	{"/vms.do", AreaVms},
+	{"/hlr.do", AreaHlr},
+	{"/sms/sendernames", AreaSendernames},
+	{"/sms/templates", AreaSmsTemplates},
+	{"/contacts", AreaContacts},
+	{"/blacklist", AreaBlacklist},
+	{"/callbacks", AreaCallbacks},
+	{"/mfa", AreaMfa},
+	{"/opt_outs", AreaOptOuts},
+	{"/profile", AreaProfile},
+	{"/subusers", AreaSubusers},
+	{"/short_url", AreaShortUrl},
+	{"/shipment", AreaShipment},
+}
+
+// RequestInfo describes an API call passing through the middleware chain.
+type RequestInfo struct {
+	// Operation names the API method called by the user, e.g. "SmsApi.SendRaw".
+	// It is empty for requests made directly through Client.Get, Client.Post...
+	Operation string
+	Area      ApiArea
+	Method    string
+	// Path is relative to the client base url, without query string.
+	Path string
+}
+
+type requestInfoKey struct{}
+
+// RequestInfoFromContext returns the description of the API call the context belongs to.
+func RequestInfoFromContext(ctx context.Context) (*RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(*RequestInfo)
+
+	return info, ok
+}
+
+func (client *Client) newRequestInfo(ctx context.Context, req *http.Request) *RequestInfo {
+	operation, _ := ctx.Value(operationKey{}).(string)
+	path := req.URL.Path
+
+	if client.BaseUrl != nil {
+		path = strings.TrimPrefix(path, strings.TrimSuffix(client.BaseUrl.Path, "/"))
+	}
+
+	return &RequestInfo{
+		Operation: operation,
+		Area:      areaFromPath(path),
+		Method:    req.Method,
+		Path:      path,
+	}
+}
+
+type operationKey struct{}
+
+// withOperation names the API method called by the user in RequestInfo.Operation. The outermost
+// method wins, so that Sms.Send reports "SmsApi.Send" even though it calls SmsApi.SendRaw.
+func withOperation(ctx context.Context, operation string) context.Context {
+	if _, ok := ctx.Value(operationKey{}).(string); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+func areaFromPath(path string) ApiArea {
+	for _, a := range areaPrefixes {
+		if path == a.prefix || strings.HasPrefix(path, a.prefix+"/") {
+			return a.area
+		}
+	}
+
+	return AreaUnknown
+}
+
+// Use appends middlewares to the chain. Middlewares run in registration order.
+func (client *Client) Use(middlewares ...Middleware) {
+	client.middlewares = append(client.middlewares, middlewares...)
+}
+
+// WithMiddleware registers middlewares on the created client, see Client.Use.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(o *options) error {
+		o.middlewares = append(o.middlewares, middlewares...)
+
+		return nil
+	}
+}
+
+// LoggingMiddleware logs method, path, area, status and latency of every API call with a
+// log.Logger, in the format of the slog text handler. It shares its records and redaction with
+// Client.Logger (see WithLogger), which should be preferred as it also logs attempts and API
+// error codes.
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	slogger := slog.New(slog.NewTextHandler(logWriter{logger}, &slog.HandlerOptions{ReplaceAttr: withoutTime}))
+
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			started := time.Now()
+
+			resp, err := next(req)
+
+			info, _ := RequestInfoFromContext(req.Context())
+
+			status := 0
+
+			if resp != nil {
+				status = resp.StatusCode
+			}
+
+			attrs := callAttrs(info, status, time.Since(started))
+			level := slog.LevelInfo
+
+			if err != nil {
+				level = slog.LevelError
+				attrs = append(attrs, LogOptions{}.errorAttrs(err)...)
+			}
+
+			slogger.LogAttrs(req.Context(), level, "smsapi request", attrs...)
+
+			return resp, err
+		}
+	}
+}
+
+// logWriter passes lines written by a slog handler to a log.Logger, keeping its prefix and flags.
+type logWriter struct {
+	logger *log.Logger
+}
+
+func (w logWriter) Write(p []byte) (int, error) {
+	return len(p), w.logger.Output(2, strings.TrimSuffix(string(p), "\n"))
+}
+
+// withoutTime drops the record time, log.Logger adds its own.
+func withoutTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+
+	return a
+}
+
+// HeaderMiddleware sets the given headers on every API request.
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+
+			for k, v := range header {
+				req.Header[http.CanonicalHeaderKey(k)] = v
+			}
+
+			return next(req)
+		}
+	}
+}
+
+type requestIdKey struct{}
+
+// ContextWithRequestId attaches a request id to be propagated by RequestIdMiddleware.
+func ContextWithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, requestId)
+}
+
+func RequestIdFromContext(ctx context.Context) (string, bool) {
+	requestId, ok := ctx.Value(requestIdKey{}).(string)
+
+	return requestId, ok && requestId != ""
+}
+
+// RequestIdMiddleware sends the request id found in the context (see ContextWithRequestId)
+// or a newly generated one in the given header, DefaultRequestIdHeader if empty.
+// The same id is used for all retries of a call.
+func RequestIdMiddleware(headerName string) Middleware {
+	if headerName == "" {
+		headerName = DefaultRequestIdHeader
+	}
+
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			requestId, ok := RequestIdFromContext(req.Context())
+
+			if !ok {
+				requestId = newRequestId()
+			}
+
+			req = req.Clone(ContextWithRequestId(req.Context(), requestId))
+			req.Header.Set(headerName, requestId)
+
+			return next(req)
+		}
+	}
+}
+
+func newRequestId() string {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
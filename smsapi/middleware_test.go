@@ -0,0 +1,223 @@
+package smsapi
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareOrderAndRequestInfo(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/contacts/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, readFixture("contacts/contact.json"))
+	})
+
+	var calls []string
+	var given *RequestInfo
+
+	record := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				given, _ = RequestInfoFromContext(req.Context())
+
+				return next(req)
+			}
+		}
+	}
+
+	client.Use(record("first"), record("second"))
+
+	if _, err := client.Contacts.GetContact(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(calls, ",") != "first,second" {
+		t.Errorf("Unexpected middleware order: %v", calls)
+	}
+
+	expected := &RequestInfo{Operation: "ContactsApi.GetContact", Area: AreaContacts, Method: "GET", Path: "/contacts/1"}
+
+	if given == nil || *given != *expected {
+		t.Errorf("Expected: %+v Given: %+v", expected, given)
+	}
+}
+
+func TestMiddlewareWrapsRetries(t *testing.T) {
+	client, mux, teardown := setupRetry(3)
+	defer teardown()
+
+	attempts := 0
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		fmt.Fprint(w, `{"username":"test"}`)
+	})
+
+	calls := 0
+
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			calls++
+
+			return next(req)
+		}
+	})
+
+	result, err := client.Profile.Details(ctx)
+
+	if err != nil || result.Username != "test" {
+		t.Fatalf("Unexpected result: %+v %v", result, err)
+	}
+
+	if calls != 1 || attempts != 2 {
+		t.Errorf("Expected one middleware call for two attempts, given: %d %d", calls, attempts)
+	}
+}
+
+func TestHeaderMiddleware(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Tenant") != "acme" {
+			t.Errorf("Expected X-Tenant header, given: %v", r.Header)
+		}
+
+		fmt.Fprint(w, `{}`)
+	})
+
+	client.Use(HeaderMiddleware(http.Header{"x-tenant": {"acme"}}))
+
+	client.Profile.Details(ctx)
+}
+
+func TestRequestIdMiddleware(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var given []string
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		given = append(given, r.Header.Get(DefaultRequestIdHeader))
+
+		fmt.Fprint(w, `{}`)
+	})
+
+	client.Use(RequestIdMiddleware(""))
+
+	client.Profile.Details(ContextWithRequestId(ctx, "request-1"))
+	client.Profile.Details(ctx)
+
+	if len(given) != 2 || given[0] != "request-1" || len(given[1]) != 32 {
+		t.Errorf("Unexpected request ids: %v", given)
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, readFixture("sms/collection.json"))
+	})
+
+	buf := new(bytes.Buffer)
+
+	client.Use(LoggingMiddleware(log.New(buf, "", 0)))
+
+	client.Sms.Send(ctx, "48100200300", "test", "")
+
+	if !strings.HasPrefix(buf.String(), `level=INFO msg="smsapi request" method=POST path=/sms.do area=sms latency=`) || !strings.Contains(buf.String(), "status=200") {
+		t.Errorf("Unexpected log: %s", buf.String())
+	}
+}
+
+func TestAreaFromPath(t *testing.T) {
+	tests := map[string]ApiArea{
+		"/sms.do":                   AreaSms,
+		"/sms/sendernames/test":     AreaSendernames,
+		"/sms/templates":            AreaSmsTemplates,
+		"/contacts/groups/1":        AreaContacts,
+		"/blacklist/phone_numbers":  AreaBlacklist,
+		"/mfa/codes/verifications":  AreaMfa,
+		"/short_url/clicks_reports": AreaShortUrl,
+		"/callbacksx":               AreaUnknown,
+		"/":                         AreaUnknown,
+	}
+
+	for path, expected := range tests {
+		if given := areaFromPath(path); given != expected {
+			t.Errorf("%s expected: %s given: %s", path, expected, given)
+		}
+	}
+}
+
+func TestRequestInfoOperationIsOutermostApiMethod(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, readFixture("sms/collection.json"))
+	})
+
+	var given string
+
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			info, _ := RequestInfoFromContext(req.Context())
+			given = info.Operation
+
+			return next(req)
+		}
+	})
+
+	client.Sms.Send(ctx, "48100200300", "test", "")
+
+	if given != "SmsApi.Send" {
+		t.Errorf("Expected SmsApi.Send, given: %q", given)
+	}
+}
+
+func TestRequestInfoOperationOfIteratorsAndDirectCalls(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/callbacks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"size":1,"collection":[{"id":"1","url":"http://example.com","type":"sms"}]}`)
+	})
+
+	var given []string
+
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			info, _ := RequestInfoFromContext(req.Context())
+			given = append(given, info.Operation)
+
+			return next(req)
+		}
+	})
+
+	for _, err := range client.Callbacks.All(ctx) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client.Get(ctx, "/callbacks", new(CallbackCollection))
+
+	if strings.Join(given, ",") != "CallbacksApi.All," {
+		t.Errorf("Unexpected operations: %q", given)
+	}
+}
@@ -20,14 +20,14 @@ type Mms struct {
 }
 
 type MmsResponse struct {
-	Id              string     `json:"id,omitempty"`
-	Points          Points     `json:"points,omitempty"`
-	Number          string     `json:"number,omitempty"`
-	DateSent        *Timestamp `json:"date_sent,omitempty"`
-	SubmittedNumber string     `json:"submitted_number,omitempty"`
-	Status          string     `json:"status,omitempty"`
-	Idx             string     `json:"idx,omitempty"`
-	Error           string     `json:"error,omitempty"`
+	Id              string        `json:"id,omitempty"`
+	Points          Points        `json:"points,omitempty"`
+	Number          string        `json:"number,omitempty"`
+	DateSent        *Timestamp    `json:"date_sent,omitempty"`
+	SubmittedNumber string        `json:"submitted_number,omitempty"`
+	Status          MessageStatus `json:"status,omitempty"`
+	Idx             string        `json:"idx,omitempty"`
+	Error           string        `json:"error,omitempty"`
 }
 
 type MmsCollectionResponse struct {
@@ -36,15 +36,19 @@ type MmsCollectionResponse struct {
 	Collection []*MmsResponse `json:"list"`
 }
 
-func (mmsApi *MmsApi) SendRaw(ctx context.Context, mms *Mms) (*MmsCollectionResponse, error) {
+func (mmsApi *MmsApi) SendRaw(ctx context.Context, mms *Mms, opts ...CallOption) (*MmsCollectionResponse, error) {
+	ctx = withOperation(ctx, "MmsApi.SendRaw")
+
 	var result = new(MmsCollectionResponse)
 
-	err := mmsApi.client.LegacyPost(ctx, "/mms.do", result, mms)
+	err := mmsApi.client.LegacyPost(ctx, "/mms.do", result, mms, opts...)
 
 	return result, err
 }
 
-func (mmsApi *MmsApi) Send(ctx context.Context, to, subject, image string) (*MmsCollectionResponse, error) {
+func (mmsApi *MmsApi) Send(ctx context.Context, to, subject, image string, opts ...CallOption) (*MmsCollectionResponse, error) {
+	ctx = withOperation(ctx, "MmsApi.Send")
+
 	smil := NewSMIL()
 	smil.AddImage(image)
 
@@ -54,10 +58,12 @@ func (mmsApi *MmsApi) Send(ctx context.Context, to, subject, image string) (*Mms
 		Message: smil,
 	}
 
-	return mmsApi.SendRaw(ctx, mms)
+	return mmsApi.SendRaw(ctx, mms, opts...)
 }
 
-func (mmsApi *MmsApi) Schedule(ctx context.Context, to, subject, image string, sendAt *Timestamp) (*MmsCollectionResponse, error) {
+func (mmsApi *MmsApi) Schedule(ctx context.Context, to, subject, image string, sendAt *Timestamp, opts ...CallOption) (*MmsCollectionResponse, error) {
+	ctx = withOperation(ctx, "MmsApi.Schedule")
+
 	smil := NewSMIL()
 	smil.AddImage(image)
 
@@ -68,10 +74,12 @@ func (mmsApi *MmsApi) Schedule(ctx context.Context, to, subject, image string, s
 		Date:    sendAt,
 	}
 
-	return mmsApi.SendRaw(ctx, mms)
+	return mmsApi.SendRaw(ctx, mms, opts...)
 }
 
-func (mmsApi *MmsApi) SendToGroup(ctx context.Context, group, subject, image string) (*MmsCollectionResponse, error) {
+func (mmsApi *MmsApi) SendToGroup(ctx context.Context, group, subject, image string, opts ...CallOption) (*MmsCollectionResponse, error) {
+	ctx = withOperation(ctx, "MmsApi.SendToGroup")
+
 	smil := NewSMIL()
 	smil.AddImage(image)
 
@@ -81,7 +89,7 @@ func (mmsApi *MmsApi) SendToGroup(ctx context.Context, group, subject, image str
 		Message: smil,
 	}
 
-	return mmsApi.SendRaw(ctx, mms)
+	return mmsApi.SendRaw(ctx, mms, opts...)
 }
 
 type MmsRemoveResponse struct {
@@ -92,19 +100,23 @@ type MmsRemoveResponse struct {
 	} `json:"list"`
 }
 
-func (mmsApi *MmsApi) RemoveScheduled(ctx context.Context, id string) (*MmsRemoveResponse, error) {
+func (mmsApi *MmsApi) RemoveScheduled(ctx context.Context, id string, opts ...CallOption) (*MmsRemoveResponse, error) {
+	ctx = withOperation(ctx, "MmsApi.RemoveScheduled")
+
 	var result = new(MmsRemoveResponse)
 
 	payload := struct {
 		SchDel string `json:"sch_del"`
 	}{SchDel: id}
 
-	err := mmsApi.client.LegacyPost(ctx, "/mms.do", result, payload)
+	err := mmsApi.client.LegacyPost(ctx, "/mms.do", result, payload, opts...)
 
 	return result, err
 }
 
-func (mmsApi *MmsApi) Get(ctx context.Context, id string) (*MmsCollectionResponse, error) {
+func (mmsApi *MmsApi) Get(ctx context.Context, id string, opts ...CallOption) (*MmsCollectionResponse, error) {
+	ctx = withOperation(ctx, "MmsApi.Get")
+
 	var result = new(MmsCollectionResponse)
 
 	v := struct {
@@ -113,7 +125,7 @@ func (mmsApi *MmsApi) Get(ctx context.Context, id string) (*MmsCollectionRespons
 
 	uri, _ := addQueryParams("/mms.do", v)
 
-	err := mmsApi.client.LegacyGet(ctx, uri, result)
+	err := mmsApi.client.LegacyGet(ctx, uri, result, opts...)
 
 	return result, err
 }
@@ -3,6 +3,9 @@ package smsapi
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/smsapi/smsapi-go/smsapi/phonenumber"
 )
 
 const optOutsApiPath = "/opt_outs"
@@ -12,9 +15,9 @@ type OptOutApi struct {
 }
 
 type OptOut struct {
-	Id          string `json:"id"`
-	PhoneNumber int64  `json:"phoneNumber"`
-	Date        string `json:"date"`
+	Id          string                  `json:"id"`
+	PhoneNumber phonenumber.PhoneNumber `json:"phoneNumber"`
+	Date        string                  `json:"date"`
 }
 
 type OptOutCollection struct {
@@ -31,26 +34,36 @@ type OptOutSettings struct {
 	Brand string `json:"brand,omitempty"`
 }
 
-func (api *OptOutApi) List(ctx context.Context, filters *OptOutCollectionFilters) (*OptOutCollection, error) {
+func (api *OptOutApi) List(ctx context.Context, filters *OptOutCollectionFilters, opts ...CallOption) (*OptOutCollection, error) {
+	ctx = withOperation(ctx, "OptOutApi.List")
 	result := new(OptOutCollection)
 	uri, _ := addQueryParams(optOutsApiPath, filters)
-	err := api.client.Get(ctx, uri, result)
+	err := api.client.Get(ctx, uri, result, opts...)
 	return result, err
 }
 
-func (api *OptOutApi) Delete(ctx context.Context, id string) error {
+// All iterates over opt-outs matching the filters, fetching further pages on demand.
+func (api *OptOutApi) All(ctx context.Context, filters *OptOutCollectionFilters, opts ...CallOption) iter.Seq2[*OptOut, error] {
+	ctx = withOperation(ctx, "OptOutApi.All")
+	return Paginate[OptOut](ctx, api.client, optOutsApiPath, filters, opts...)
+}
+
+func (api *OptOutApi) Delete(ctx context.Context, id string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "OptOutApi.Delete")
 	uri := fmt.Sprintf("%s/%s", optOutsApiPath, id)
-	return api.client.Delete(ctx, uri)
+	return api.client.Delete(ctx, uri, opts...)
 }
 
-func (api *OptOutApi) GetSettings(ctx context.Context) (*OptOutSettings, error) {
+func (api *OptOutApi) GetSettings(ctx context.Context, opts ...CallOption) (*OptOutSettings, error) {
+	ctx = withOperation(ctx, "OptOutApi.GetSettings")
 	result := new(OptOutSettings)
-	err := api.client.Get(ctx, "/opt_outs/settings", result)
+	err := api.client.Get(ctx, "/opt_outs/settings", result, opts...)
 	return result, err
 }
 
-func (api *OptOutApi) UpdateSettings(ctx context.Context, settings *OptOutSettings) (*OptOutSettings, error) {
+func (api *OptOutApi) UpdateSettings(ctx context.Context, settings *OptOutSettings, opts ...CallOption) (*OptOutSettings, error) {
+	ctx = withOperation(ctx, "OptOutApi.UpdateSettings")
 	result := new(OptOutSettings)
-	err := api.client.Put(ctx, "/opt_outs/settings", result, settings)
+	err := api.client.Put(ctx, "/opt_outs/settings", result, settings, opts...)
 	return result, err
 }
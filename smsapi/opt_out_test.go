@@ -19,7 +19,22 @@ func TestOptOutList(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if result.Size != 1 || result.Collection[0].Id != "1" {
+	if result.Size != 1 || result.Collection[0].Id != "1" || result.Collection[0].PhoneNumber.String() != "48500500500" {
+		t.Errorf("Unexpected: %+v", result)
+	}
+}
+
+func TestOptOutListKeepsUnknownNumbers(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+	mux.HandleFunc("/opt_outs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"size":2,"collection":[{"id":"1","phoneNumber":800123},{"id":"2","phoneNumber":48500500500}]}`)
+	})
+	result, err := client.OptOut.List(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Collection) != 2 || result.Collection[0].PhoneNumber.String() != "800123" || !result.Collection[1].PhoneNumber.IsValid() {
 		t.Errorf("Unexpected: %+v", result)
 	}
 }
@@ -0,0 +1,241 @@
+package smsapi
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Region string
+
+const (
+	RegionPl  = Region("pl")
+	RegionCom = Region("com")
+)
+
+var regionBaseUrls = map[Region]string{
+	RegionPl:  BaseUrlPl,
+	RegionCom: BaseUrlCom,
+}
+
+var (
+	ErrMissingToken   = errors.New("smsapi: access token is required")
+	ErrInvalidBaseUrl = errors.New("smsapi: invalid base url")
+)
+
+// Option configures a Client created by New.
+type Option func(*options) error
+
+type options struct {
+	baseUrl           string
+	region            Region
+	token             string
+	httpClient        *http.Client
+	timeout           *time.Duration
+	userAgentSuffix   string
+	retryPolicy       *RetryPolicy
+	rateLimiter       *RateLimiter
+	middlewares       []Middleware
+	logger            *slog.Logger
+	logOptions        LogOptions
+	phoneNumberPolicy *PhoneNumberPolicy
+	mmsVms            *bool
+}
+
+// WithBaseURL overrides the API url, e.g. to point the client to a proxy. It takes
+// precedence over WithRegion.
+func WithBaseURL(baseUrl string) Option {
+	return func(o *options) error {
+		o.baseUrl = baseUrl
+
+		return nil
+	}
+}
+
+// WithRegion selects smsapi.pl (RegionPl, default) or smsapi.com (RegionCom) API.
+func WithRegion(region Region) Option {
+	return func(o *options) error {
+		if _, ok := regionBaseUrls[region]; !ok {
+			return fmt.Errorf("smsapi: unknown region %q", region)
+		}
+
+		o.region = region
+
+		return nil
+	}
+}
+
+func WithToken(accessToken string) Option {
+	return func(o *options) error {
+		o.token = accessToken
+
+		return nil
+	}
+}
+
+// WithHTTPClient sets the http.Client used to execute requests. Nil keeps the default
+// client which has DefaultTimeout set.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) error {
+		if httpClient != nil {
+			o.httpClient = httpClient
+		}
+
+		return nil
+	}
+}
+
+// WithTimeout sets the overall request timeout. When combined with WithHTTPClient the
+// given client is copied, so the caller's instance is never modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) error {
+		if timeout < 0 {
+			return fmt.Errorf("smsapi: negative timeout %s", timeout)
+		}
+
+		o.timeout = &timeout
+
+		return nil
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier to the User-Agent header.
+func WithUserAgentSuffix(suffix string) Option {
+	return func(o *options) error {
+		o.userAgentSuffix = suffix
+
+		return nil
+	}
+}
+
+// WithRetryPolicy enables automatic retries, see RetryPolicy.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(o *options) error {
+		if err := policy.validate(); err != nil {
+			return err
+		}
+
+		o.retryPolicy = policy
+
+		return nil
+	}
+}
+
+func withMmsVms(enabled bool) Option {
+	return func(o *options) error {
+		o.mmsVms = &enabled
+
+		return nil
+	}
+}
+
+// New creates a Client configured with the given options and validates the result.
+//
+//	client, err := smsapi.New(smsapi.WithToken(token), smsapi.WithRegion(smsapi.RegionCom))
+//
+// MMS and VMS APIs are available unless the client targets smsapi.com.
+func New(opts ...Option) (*Client, error) {
+	o, err := applyOptions(opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if o.token == "" {
+		return nil, ErrMissingToken
+	}
+
+	baseUrl, err := url.Parse(o.resolveBaseUrl())
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBaseUrl, err)
+	}
+
+	if baseUrl.Scheme == "" || baseUrl.Host == "" {
+		return nil, fmt.Errorf("%w: %q is not an absolute url", ErrInvalidBaseUrl, o.resolveBaseUrl())
+	}
+
+	return newClient(o, baseUrl), nil
+}
+
+func applyOptions(opts []Option) (*options, error) {
+	o := &options{region: RegionPl}
+
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return o, err
+		}
+	}
+
+	return o, nil
+}
+
+func (o *options) resolveBaseUrl() string {
+	if o.baseUrl != "" {
+		return o.baseUrl
+	}
+
+	return regionBaseUrls[o.region]
+}
+
+func (o *options) resolveHttpClient() *http.Client {
+	if o.httpClient == nil {
+		timeout := DefaultTimeout
+
+		if o.timeout != nil {
+			timeout = *o.timeout
+		}
+
+		return &http.Client{Timeout: timeout}
+	}
+
+	if o.timeout == nil {
+		return o.httpClient
+	}
+
+	httpClient := *o.httpClient
+	httpClient.Timeout = *o.timeout
+
+	return &httpClient
+}
+
+func (o *options) resolveMmsVms() bool {
+	if o.mmsVms != nil {
+		return *o.mmsVms
+	}
+
+	return o.resolveBaseUrl() != BaseUrlCom
+}
+
+func (o *options) userAgent() string {
+	userAgent := fmt.Sprintf("%s/%s", Name, Version)
+
+	if o.userAgentSuffix != "" {
+		userAgent = userAgent + " " + o.userAgentSuffix
+	}
+
+	return userAgent
+}
+
+func (p *RetryPolicy) validate() error {
+	if p == nil {
+		return nil
+	}
+
+	if p.MaxAttempts < 1 {
+		return errors.New("smsapi: retry policy requires at least one attempt")
+	}
+
+	if p.BaseDelay < 0 || p.MaxDelay < 0 {
+		return errors.New("smsapi: retry policy delays must not be negative")
+	}
+
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return errors.New("smsapi: retry policy jitter must be between 0 and 1")
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package smsapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewDefaults(t *testing.T) {
+	client, err := New(WithToken("token"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.BaseUrl.String() != BaseUrlPl {
+		t.Errorf("Expected: %s Given: %s", BaseUrlPl, client.BaseUrl)
+	}
+
+	if client.httpClient == http.DefaultClient || client.httpClient.Timeout != DefaultTimeout {
+		t.Errorf("Expected own http client with default timeout, given: %+v", client.httpClient)
+	}
+
+	if client.Mms == nil || client.Vms == nil {
+		t.Error("Expected MMS and VMS APIs for smsapi.pl")
+	}
+}
+
+func TestNewRegionCom(t *testing.T) {
+	client, err := New(WithToken("token"), WithRegion(RegionCom))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.BaseUrl.String() != BaseUrlCom {
+		t.Errorf("Expected: %s Given: %s", BaseUrlCom, client.BaseUrl)
+	}
+
+	if client.Mms != nil || client.Vms != nil {
+		t.Error("Expected no MMS and VMS APIs for smsapi.com")
+	}
+}
+
+func TestNewBaseUrlOverridesRegion(t *testing.T) {
+	client, err := New(WithToken("token"), WithBaseURL("http://localhost:8080/"), WithRegion(RegionCom))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.BaseUrl.String() != "http://localhost:8080/" {
+		t.Errorf("Unexpected base url: %s", client.BaseUrl)
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		err  error
+	}{
+		{"missing token", nil, ErrMissingToken},
+		{"relative url", []Option{WithToken("t"), WithBaseURL("api.smsapi.pl")}, ErrInvalidBaseUrl},
+		{"malformed url", []Option{WithToken("t"), WithBaseURL("http://[::1")}, ErrInvalidBaseUrl},
+		{"unknown region", []Option{WithToken("t"), WithRegion("de")}, nil},
+		{"negative timeout", []Option{WithToken("t"), WithTimeout(-time.Second)}, nil},
+		{"invalid retry policy", []Option{WithToken("t"), WithRetryPolicy(&RetryPolicy{})}, nil},
+	}
+
+	for _, test := range tests {
+		client, err := New(test.opts...)
+
+		if err == nil || client != nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+
+		if test.err != nil && !errors.Is(err, test.err) {
+			t.Errorf("%s: expected: %v given: %v", test.name, test.err, err)
+		}
+	}
+}
+
+func TestNewTimeoutDoesNotModifyGivenClient(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Minute}
+
+	client, err := New(WithToken("token"), WithHTTPClient(httpClient), WithTimeout(time.Second))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if httpClient.Timeout != time.Minute {
+		t.Errorf("Given http client was modified: %v", httpClient.Timeout)
+	}
+
+	if client.httpClient.Timeout != time.Second {
+		t.Errorf("Expected timeout: 1s Given: %v", client.httpClient.Timeout)
+	}
+}
+
+func TestNewUserAgentSuffix(t *testing.T) {
+	client, _ := New(WithToken("token"), WithUserAgentSuffix("my-app/1.0"))
+
+	req, _ := client.NewJsonRequest("GET", "/profile", nil)
+
+	expected := Name + "/" + Version + " my-app/1.0"
+
+	if given := req.Header.Get("User-Agent"); given != expected {
+		t.Errorf("Expected: %s Given: %s", expected, given)
+	}
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+	policy := DefaultRetryPolicy()
+
+	client, _ := New(WithToken("token"), WithRetryPolicy(policy))
+
+	if client.RetryPolicy != policy {
+		t.Errorf("Expected retry policy to be set")
+	}
+}
+
+func TestLegacyConstructorsDoNotTouchDefaultClient(t *testing.T) {
+	timeout := http.DefaultClient.Timeout
+
+	client := NewPlClient("token", nil)
+
+	if http.DefaultClient.Timeout != timeout {
+		t.Errorf("http.DefaultClient was modified")
+	}
+
+	if client.httpClient == http.DefaultClient || client.Mms == nil {
+		t.Errorf("Unexpected client: %+v", client)
+	}
+
+	if international := NewInternationalClient("token", nil); international.Mms != nil {
+		t.Errorf("Expected no MMS API for international client")
+	}
+}
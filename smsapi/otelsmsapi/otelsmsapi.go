@@ -0,0 +1,238 @@
+// Package otelsmsapi instruments smsapi.Client with OpenTelemetry traces and metrics.
+//
+//	client, _ := smsapi.New(smsapi.WithToken(token))
+//	otelsmsapi.Instrument(client)
+//
+// Every API call produces a client span named after the operation, e.g. "SmsApi.SendRaw",
+// or after the method and path template for direct calls, e.g. "GET /contacts/{id}". It is
+// counted by the following instruments:
+//
+//	smsapi.client.requests  counter    all calls
+//	smsapi.client.failures  counter    failed calls, by error code
+//	smsapi.client.duration  histogram  call duration in seconds, including retries
+//	smsapi.client.points    counter    points spent on sent messages and HLR lookups
+package otelsmsapi
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/smsapi/smsapi-go/smsapi"
+	"github.com/smsapi/smsapi-go/smsapi/internal/callstats"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const ScopeName = "github.com/smsapi/smsapi-go/smsapi/otelsmsapi"
+
+const (
+	AttributeOperation    = attribute.Key("smsapi.operation")
+	AttributeArea         = attribute.Key("smsapi.area")
+	AttributeEndpoint     = attribute.Key("smsapi.endpoint")
+	AttributeErrorCode    = attribute.Key("smsapi.error_code")
+	AttributeMessageParts = attribute.Key("smsapi.message_parts")
+	AttributePoints       = attribute.Key("smsapi.points")
+
+	attributeMethod     = attribute.Key("http.request.method")
+	attributeStatusCode = attribute.Key("http.response.status_code")
+)
+
+// Option configures the instrumentation.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// WithTracerProvider sets the tracer provider, the global one is used by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider, the global one is used by default.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// Instrument registers the instrumentation middleware on the client.
+func Instrument(client *smsapi.Client, opts ...Option) error {
+	middleware, err := NewMiddleware(opts...)
+
+	if err != nil {
+		return err
+	}
+
+	client.Use(middleware)
+
+	return nil
+}
+
+type instruments struct {
+	tracer   trace.Tracer
+	requests metric.Int64Counter
+	failures metric.Int64Counter
+	duration metric.Float64Histogram
+	points   metric.Float64Counter
+}
+
+// NewMiddleware creates the instrumentation middleware, see smsapi.Client.Use.
+// Register it first so that its spans cover the remaining middlewares and retries.
+func NewMiddleware(opts ...Option) (smsapi.Middleware, error) {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	i, err := newInstruments(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return i.middleware, nil
+}
+
+func newInstruments(c *config) (*instruments, error) {
+	meter := c.meterProvider.Meter(ScopeName)
+
+	i := &instruments{tracer: c.tracerProvider.Tracer(ScopeName)}
+
+	var err error
+
+	if i.requests, err = meter.Int64Counter("smsapi.client.requests",
+		metric.WithDescription("Number of SMSAPI calls."),
+	); err != nil {
+		return nil, err
+	}
+
+	if i.failures, err = meter.Int64Counter("smsapi.client.failures",
+		metric.WithDescription("Number of failed SMSAPI calls."),
+	); err != nil {
+		return nil, err
+	}
+
+	if i.duration, err = meter.Float64Histogram("smsapi.client.duration",
+		metric.WithDescription("Duration of SMSAPI calls including retries."),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, err
+	}
+
+	if i.points, err = meter.Float64Counter("smsapi.client.points",
+		metric.WithDescription("Points spent on SMSAPI calls."),
+	); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func (i *instruments) middleware(next smsapi.RoundTripFunc) smsapi.RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		info, _ := smsapi.RequestInfoFromContext(req.Context())
+
+		if info == nil {
+			info = &smsapi.RequestInfo{Method: req.Method, Path: req.URL.Path, Area: smsapi.AreaUnknown}
+		}
+
+		common := []attribute.KeyValue{
+			AttributeOperation.String(spanName(info)),
+			AttributeArea.String(string(info.Area)),
+		}
+
+		ctx, span := i.tracer.Start(req.Context(), spanName(info),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(common...),
+			trace.WithAttributes(
+				AttributeEndpoint.String(info.Path),
+				attributeMethod.String(info.Method),
+			),
+		)
+		defer span.End()
+
+		started := time.Now()
+
+		resp, err := next(req.WithContext(ctx))
+
+		i.duration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(common...))
+
+		result := callstats.Inspect(req, resp, err, info.Area)
+
+		if resp != nil {
+			span.SetAttributes(attributeStatusCode.Int(resp.StatusCode))
+		}
+
+		if result.Parts > 0 {
+			span.SetAttributes(AttributeMessageParts.Int(result.Parts))
+		}
+
+		if result.Send && result.Points > 0 {
+			span.SetAttributes(AttributePoints.Float64(result.Points))
+			i.points.Add(ctx, result.Points, metric.WithAttributes(common...))
+		}
+
+		i.requests.Add(ctx, 1, metric.WithAttributes(common...))
+
+		if result.Failed {
+			span.SetAttributes(AttributeErrorCode.String(result.ErrorCode))
+			span.SetStatus(codes.Error, result.Message)
+
+			if err != nil {
+				span.RecordError(err)
+			}
+
+			i.failures.Add(ctx, 1, metric.WithAttributes(append(common, AttributeErrorCode.String(result.ErrorCode))...))
+		}
+
+		return resp, err
+	}
+}
+
+func spanName(info *smsapi.RequestInfo) string {
+	if info.Operation != "" {
+		return info.Operation
+	}
+
+	return info.Method + " " + pathTemplate(info.Path)
+}
+
+// pathSegments are the fixed segments of API paths, other segments are ids or names.
+var pathSegments = map[string]bool{}
+
+func init() {
+	for _, segment := range strings.Fields(`
+		activate available blacklist callbacks clicks clicks_reports codes commands contacts
+		country_volumes deactivate fields groups hlr.do imports links make_default members mfa
+		mms.do opt_outs options permissions phone_numbers prices profile restore sendernames
+		settings shares shipment short_url sms sms.do statement subusers templates test trash
+		verifications vms.do`) {
+		pathSegments[segment] = true
+	}
+}
+
+// pathTemplate replaces ids in the path with "{id}", keeping the cardinality of span names and
+// metric attributes bounded.
+func pathTemplate(path string) string {
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		if segment != "" && !pathSegments[segment] {
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,254 @@
+package otelsmsapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smsapi/smsapi-go/smsapi"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var ctx = context.Background()
+
+func setup(t *testing.T) (*smsapi.Client, *http.ServeMux, *tracetest.SpanRecorder, *sdkmetric.ManualReader, func()) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	client, err := smsapi.New(smsapi.WithBaseURL(server.URL+"/"), smsapi.WithToken("token"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	err = Instrument(client,
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client, mux, spans, reader, server.Close
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attributes := map[attribute.Key]attribute.Value{}
+
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+
+	return attributes
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	var rm metricdata.ResourceMetrics
+
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	metrics := map[string]metricdata.Aggregation{}
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	return metrics
+}
+
+func TestSendSpanAndMetrics(t *testing.T) {
+	client, mux, spans, reader, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"count":2,"list":[{"id":"1","points":0.25,"parts":2},{"id":"2","points":0.25,"parts":2}]}`)
+	})
+
+	if _, err := client.Sms.SendRaw(ctx, &smsapi.Sms{To: "48100200300,48100200301", Message: "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ended := spans.Ended()
+
+	if len(ended) != 1 || ended[0].Name() != "SmsApi.SendRaw" {
+		t.Fatalf("Expected SmsApi.SendRaw span, given: %v", ended)
+	}
+
+	attributes := spanAttributes(ended[0])
+
+	if attributes[AttributeEndpoint].AsString() != "/sms.do" ||
+		attributes[AttributeArea].AsString() != string(smsapi.AreaSms) ||
+		attributes[attributeStatusCode].AsInt64() != 200 ||
+		attributes[AttributeMessageParts].AsInt64() != 4 ||
+		attributes[AttributePoints].AsFloat64() != 0.5 {
+		t.Errorf("Unexpected span attributes: %v", attributes)
+	}
+
+	metrics := collect(t, reader)
+
+	requests := metrics["smsapi.client.requests"].(metricdata.Sum[int64])
+
+	if requests.DataPoints[0].Value != 1 {
+		t.Errorf("Expected 1 request, given: %v", requests.DataPoints)
+	}
+
+	points := metrics["smsapi.client.points"].(metricdata.Sum[float64])
+
+	if points.DataPoints[0].Value != 0.5 {
+		t.Errorf("Expected 0.5 points, given: %v", points.DataPoints)
+	}
+
+	duration := metrics["smsapi.client.duration"].(metricdata.Histogram[float64])
+
+	if duration.DataPoints[0].Count != 1 {
+		t.Errorf("Expected 1 duration sample, given: %v", duration.DataPoints)
+	}
+
+	if _, ok := metrics["smsapi.client.failures"]; ok {
+		t.Error("Expected no failures")
+	}
+}
+
+func TestApiErrorSpanAndMetrics(t *testing.T) {
+	client, mux, spans, reader, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/contacts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"invalid_request_data","message":"Invalid phone number"}`)
+	})
+
+	client.Contacts.CreateContact(ctx, &smsapi.Contact{PhoneNumber: "1"})
+
+	span := spans.Ended()[0]
+
+	if span.Name() != "ContactsApi.CreateContact" || span.Status().Code != codes.Error {
+		t.Errorf("Unexpected span: %s %v", span.Name(), span.Status())
+	}
+
+	if code := spanAttributes(span)[AttributeErrorCode].AsString(); code != "invalid_request_data" {
+		t.Errorf("Expected invalid_request_data, given: %s", code)
+	}
+
+	failures := collect(t, reader)["smsapi.client.failures"].(metricdata.Sum[int64])
+
+	dp := failures.DataPoints[0]
+
+	if code, _ := dp.Attributes.Value(AttributeErrorCode); dp.Value != 1 || code.AsString() != "invalid_request_data" {
+		t.Errorf("Unexpected failures: %v", failures.DataPoints)
+	}
+}
+
+func TestLegacyErrorCode(t *testing.T) {
+	client, mux, spans, _, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"error":103,"message":"Not enough points"}`)
+	})
+
+	client.Sms.Send(ctx, "48100200300", "test", "")
+
+	span := spans.Ended()[0]
+
+	if code := spanAttributes(span)[AttributeErrorCode].AsString(); code != "103" || span.Name() != "SmsApi.Send" {
+		t.Errorf("Unexpected span: %s %s", span.Name(), code)
+	}
+}
+
+func TestResponseBodyIsPreserved(t *testing.T) {
+	client, mux, _, _, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"username":"test","points":100}`)
+	})
+
+	result, err := client.Profile.Details(ctx)
+
+	if err != nil || result.Username != "test" {
+		t.Errorf("Expected decoded profile, given: %+v %v", result, err)
+	}
+}
+
+func TestStatusLookupSpendsNoPoints(t *testing.T) {
+	client, mux, spans, reader, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"count":1,"list":[{"id":"1","points":0.16,"parts":1,"status":"DELIVERED"}]}`)
+	})
+
+	if _, err := client.Sms.Get(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := spanAttributes(spans.Ended()[0])[AttributePoints]; ok {
+		t.Error("Expected no points attribute")
+	}
+
+	if _, ok := collect(t, reader)["smsapi.client.points"]; ok {
+		t.Error("Expected no points spent")
+	}
+}
+
+func TestDirectCallOperationIsPathTemplate(t *testing.T) {
+	client, mux, spans, reader, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/contacts/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"1"}`)
+	})
+
+	for _, id := range []string{"1", "2"} {
+		if err := client.Get(ctx, "/contacts/"+id+"/groups", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, span := range spans.Ended() {
+		if span.Name() != "GET /contacts/{id}/groups" {
+			t.Errorf("Unexpected span name: %s", span.Name())
+		}
+	}
+
+	requests := collect(t, reader)["smsapi.client.requests"].(metricdata.Sum[int64])
+
+	if len(requests.DataPoints) != 1 || requests.DataPoints[0].Value != 2 {
+		t.Errorf("Expected a single series, given: %v", requests.DataPoints)
+	}
+
+	if operation, _ := requests.DataPoints[0].Attributes.Value(AttributeOperation); operation.AsString() != "GET /contacts/{id}/groups" {
+		t.Errorf("Unexpected operation: %v", operation)
+	}
+}
+
+func TestPathTemplate(t *testing.T) {
+	tests := map[string]string{
+		"/contacts/5f2b/groups/7":                     "/contacts/{id}/groups/{id}",
+		"/sms/sendernames/Shop/commands/make_default": "/sms/sendernames/{id}/commands/make_default",
+		"/blacklist/phone_numbers":                    "/blacklist/phone_numbers",
+		"/sms.do":                                     "/sms.do",
+	}
+
+	for path, expected := range tests {
+		if given := pathTemplate(path); given != expected {
+			t.Errorf("%s expected: %s given: %s", path, expected, given)
+		}
+	}
+}
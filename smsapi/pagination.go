@@ -2,11 +2,16 @@ package smsapi
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/google/go-querystring/query"
+	"iter"
+	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 const DefaultPageSize = 100
@@ -18,6 +23,10 @@ type PaginationFilters struct {
 	Limit  uint `url:"limit,omitempty"`
 }
 
+// PageIterator fetches consecutive pages of a collection. It stops when Offset reaches
+// the collection Size or an empty page is returned, and follows the next page link
+// when the API provides one, either as a "next" field or a Link header.
+// It is safe for concurrent use.
 type PageIterator struct {
 	Client  *Client
 	Context context.Context
@@ -26,47 +35,180 @@ type PageIterator struct {
 	Size    uint
 	Limit   uint
 	Offset  uint
+
+	// CallOptions are applied to every page request.
+	CallOptions []CallOption
+
+	// Prefetch fetches the following page in background while the current one is consumed.
+	Prefetch bool
+
+	mu      sync.Mutex
+	next    string
+	done    bool
+	pending chan *rawPage
+}
+
+type rawPage struct {
+	uri  string
+	body json.RawMessage
+	info pageInfo
+	next string
+	err  error
+}
+
+type pageInfo struct {
+	Size       *uint             `json:"size"`
+	Collection []json.RawMessage `json:"collection"`
+	Next       string            `json:"next"`
 }
 
 func (i *PageIterator) Next(result Collection) error {
-	if i.noMoreResults() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.done {
 		return NoMoreResults
 	}
 
-	err := i.nextPage(result)
+	page := i.take()
 
-	if err != nil {
+	if page.err != nil {
+		return page.err
+	}
+
+	if err := json.Unmarshal(page.body, result); err != nil {
 		return err
 	}
 
-	i.Offset = i.Offset + i.Limit
-	i.Size = result.GetSize()
+	i.advance(page, result)
+
+	if i.Prefetch && !i.done {
+		i.prefetch()
+	}
 
 	return nil
 }
 
-func (i *PageIterator) nextPage(result Collection) error {
+// take returns the prefetched page or fetches the current one.
+func (i *PageIterator) take() *rawPage {
+	if i.pending != nil {
+		page := <-i.pending
+		i.pending = nil
+
+		if page.uri == i.pageUri() {
+			return page
+		}
+	}
+
+	return i.fetch(i.pageUri())
+}
+
+func (i *PageIterator) prefetch() {
+	uri := i.pageUri()
+	pending := make(chan *rawPage, 1)
+
+	go func() {
+		pending <- i.fetch(uri)
+	}()
+
+	i.pending = pending
+}
+
+func (i *PageIterator) pageUri() string {
+	if i.next != "" {
+		return i.next
+	}
+
+	if i.Filters == nil {
+		i.Filters = url.Values{}
+	}
+
 	i.Filters.Set("offset", fmt.Sprint(i.Offset))
 	i.Filters.Set("limit", fmt.Sprint(i.Limit))
 
 	u, err := url.Parse(i.Uri)
 
 	if err != nil {
-		return err
+		return i.Uri
 	}
 
 	u.RawQuery = i.Filters.Encode()
 
-	err = i.Client.Get(i.Context, u.String(), result)
+	return u.String()
+}
+
+func (i *PageIterator) fetch(uri string) *rawPage {
+	page := &rawPage{uri: uri}
+
+	var meta Response
+
+	opts := append(i.CallOptions[:len(i.CallOptions):len(i.CallOptions)], WithResponse(&meta))
+
+	page.err = i.Client.Get(i.Context, uri, &page.body, opts...)
+
+	if page.err != nil {
+		return page
+	}
 
-	return err
+	if len(page.body) == 0 {
+		page.body = json.RawMessage("{}")
+	}
+
+	page.err = json.Unmarshal(page.body, &page.info)
+	page.next = page.info.Next
+
+	if page.next == "" {
+		page.next = nextLink(meta.Header)
+	}
+
+	return page
 }
 
-func (i *PageIterator) noMoreResults() bool {
-	return i.Size > 0 && i.Offset > i.Size
+func (i *PageIterator) advance(page *rawPage, result Collection) {
+	count := uint(len(page.info.Collection))
+
+	i.Offset += count
+
+	if page.info.Size != nil {
+		i.Size = *page.info.Size
+	} else {
+		i.Size = result.GetSize()
+	}
+
+	i.next = ""
+
+	switch {
+	case count == 0:
+		i.done = true
+	case page.next != "" && page.next != page.uri:
+		i.next = page.next
+	case i.Size > 0 && i.Offset >= i.Size:
+		i.done = true
+	case i.Size == 0 && count < i.Limit:
+		// Size is not reported, a short page is the last one.
+		i.done = true
+	}
+}
+
+// nextLink returns the rel="next" target of the Link header.
+func nextLink(header http.Header) string {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+
+			for _, param := range parts[1:] {
+				if strings.ReplaceAll(strings.TrimSpace(param), " ", "") == `rel="next"` {
+					return target
+				}
+			}
+		}
+	}
+
+	return ""
 }
 
-func NewPageIterator(c *Client, ctx context.Context, uri string, v interface{}) *PageIterator {
+func NewPageIterator(c *Client, ctx context.Context, uri string, v interface{}, opts ...CallOption) *PageIterator {
 	var offset, limit uint64
 
 	filters, err := query.Values(v)
@@ -96,5 +238,66 @@ func NewPageIterator(c *Client, ctx context.Context, uri string, v interface{})
 		Filters: filters,
 		Offset:  uint(offset),
 		Limit:   uint(limit),
+
+		CallOptions: opts,
+	}
+}
+
+// Page is a single page of a collection returned by the API.
+type Page[T any] struct {
+	Size       uint `json:"size"`
+	Collection []*T `json:"collection"`
+}
+
+func (p *Page[T]) GetSize() uint {
+	return p.Size
+}
+
+// Pages iterates over pages of the collection at uri, starting at the offset and
+// limit given in filters. Iteration stops after the first error.
+//
+//	for page, err := range smsapi.Pages[smsapi.Contact](ctx, client, "/contacts", filters) {
+//		...
+//	}
+func Pages[T any](ctx context.Context, client *Client, uri string, filters interface{}, opts ...CallOption) iter.Seq2[*Page[T], error] {
+	return func(yield func(*Page[T], error) bool) {
+		i := NewPageIterator(client, ctx, uri, filters, opts...)
+
+		for {
+			page := new(Page[T])
+
+			err := i.Next(page)
+
+			if err == NoMoreResults {
+				return
+			}
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yield(page, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Paginate iterates over individual items of the collection at uri, see Pages.
+func Paginate[T any](ctx context.Context, client *Client, uri string, filters interface{}, opts ...CallOption) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		for page, err := range Pages[T](ctx, client, uri, filters, opts...) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, item := range page.Collection {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
 	}
 }
@@ -1,11 +1,16 @@
 package smsapi
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 type AnyCollection struct {
@@ -46,7 +51,7 @@ func TestGetNextPage(t *testing.T) {
 	skipAssert := true
 
 	mux.HandleFunc("/uri", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprint(w, "{}")
+		fmt.Fprintf(w, `{"size":200,"collection":[%s]}`, strings.TrimSuffix(strings.Repeat("{},", 100), ","))
 
 		expected := url.Values{}
 		expected.Add("offset", "100")
@@ -88,3 +93,351 @@ func assertPaginationFilters(t *testing.T, expected url.Values, given url.Values
 		t.Errorf("Query string not equals. Expected: %v Given :%v", expected, given)
 	}
 }
+
+type item struct {
+	Id string `json:"id"`
+}
+
+func servePages(mux *http.ServeMux, uri string, size int, calls *int) {
+	mux.HandleFunc(uri, func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var items []string
+
+		for i := offset; i < offset+limit && i < size; i++ {
+			items = append(items, fmt.Sprintf(`{"id":"%d"}`, i))
+		}
+
+		fmt.Fprintf(w, `{"size":%d,"collection":[%s]}`, size, strings.Join(items, ","))
+	})
+}
+
+func TestPaginateYieldsAllItems(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	servePages(mux, "/uri", 5, &calls)
+
+	var ids []string
+
+	for item, err := range Paginate[item](ctx, client, "/uri", &PaginationFilters{Limit: 2}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids = append(ids, item.Id)
+	}
+
+	if strings.Join(ids, ",") != "0,1,2,3,4" || calls != 3 {
+		t.Errorf("Unexpected items: %v calls: %d", ids, calls)
+	}
+}
+
+func TestPaginateStopsOnBreak(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := 0
+	servePages(mux, "/uri", 10, &calls)
+
+	for item := range Paginate[item](ctx, client, "/uri", &PaginationFilters{Limit: 2}) {
+		if item.Id == "2" {
+			break
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, given: %d", calls)
+	}
+}
+
+func TestPaginateYieldsError(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/uri", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":"unauthorized","message":"Authorization failed"}`)
+	})
+
+	count := 0
+
+	for item, err := range Paginate[item](ctx, client, "/uri", nil) {
+		count++
+
+		if item != nil || !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("Expected unauthorized error, given: %v %v", item, err)
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("Expected a single error, given: %d", count)
+	}
+}
+
+func TestPagesStopsOnEmptyPage(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := 0
+
+	mux.HandleFunc("/uri", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		fmt.Fprint(w, `{"size":0,"collection":[]}`)
+	})
+
+	pages := 0
+
+	for _, err := range Pages[item](ctx, client, "/uri", nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pages++
+	}
+
+	if pages != 1 || calls != 1 {
+		t.Errorf("Expected a single empty page, given: %d pages %d calls", pages, calls)
+	}
+}
+
+func TestPagesStopsOnEmptyPageWithNextLink(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := 0
+
+	mux.HandleFunc("/uri", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		fmt.Fprintf(w, `{"collection":[],"next":"/uri?cursor=%d"}`, calls)
+	})
+
+	for _, err := range Pages[item](ctx, client, "/uri", nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected a single call, given: %d", calls)
+	}
+}
+
+type pageServer struct {
+	size        int
+	hideSize    bool
+	ignoreLimit bool
+	nextField   bool
+	linkHeader  bool
+}
+
+func (p pageServer) handler(calls *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*calls = append(*calls, r.URL.RawQuery)
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		if p.ignoreLimit {
+			offset, limit = 0, p.size
+		}
+
+		var items []string
+
+		for i := offset; i < offset+limit && i < p.size; i++ {
+			items = append(items, fmt.Sprintf(`{"id":"%d"}`, i))
+		}
+
+		fields := []string{fmt.Sprintf(`"collection":[%s]`, strings.Join(items, ","))}
+
+		if !p.hideSize {
+			fields = append(fields, fmt.Sprintf(`"size":%d`, p.size))
+		}
+
+		if next := offset + limit; next < p.size {
+			nextUri := fmt.Sprintf("/uri?cursor=%d&offset=%d&limit=%d", next, next, limit)
+
+			if p.nextField {
+				fields = append(fields, fmt.Sprintf(`"next":%q`, nextUri))
+			}
+
+			if p.linkHeader {
+				w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next", </uri>; rel="first"`, nextUri))
+			}
+		}
+
+		fmt.Fprintf(w, "{%s}", strings.Join(fields, ","))
+	}
+}
+
+func TestPageIteratorTermination(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   pageServer
+		limit    uint
+		expected []string
+	}{
+		{"exact multiple of limit", pageServer{size: 4}, 2, []string{"limit=2&offset=0", "limit=2&offset=2"}},
+		{"partial last page", pageServer{size: 5}, 2, []string{"limit=2&offset=0", "limit=2&offset=2", "limit=2&offset=4"}},
+		{"empty collection", pageServer{size: 0}, 2, []string{"limit=2&offset=0"}},
+		{"size not reported, short page", pageServer{size: 3, hideSize: true}, 2, []string{"limit=2&offset=0", "limit=2&offset=2"}},
+		{"size not reported, empty page", pageServer{size: 4, hideSize: true}, 2, []string{"limit=2&offset=0", "limit=2&offset=2", "limit=2&offset=4"}},
+		{"limit ignored by api", pageServer{size: 5, ignoreLimit: true}, 2, []string{"limit=2&offset=0"}},
+		{"next field", pageServer{size: 3, nextField: true}, 2, []string{"limit=2&offset=0", "cursor=2&offset=2&limit=2"}},
+		{"link header", pageServer{size: 3, linkHeader: true}, 2, []string{"limit=2&offset=0", "cursor=2&offset=2&limit=2"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, mux, teardown := setup()
+			defer teardown()
+
+			var calls []string
+
+			mux.HandleFunc("/uri", test.server.handler(&calls))
+
+			iterator := NewPageIterator(client, ctx, "/uri", &PaginationFilters{Limit: test.limit})
+
+			var err error
+
+			for pages := 0; pages < 10 && err == nil; pages++ {
+				err = iterator.Next(new(Page[item]))
+			}
+
+			if err != NoMoreResults {
+				t.Errorf("Expected NoMoreResults, given: %v", err)
+			}
+
+			if !reflect.DeepEqual(calls, test.expected) {
+				t.Errorf("Expected requests: %v given: %v", test.expected, calls)
+			}
+		})
+	}
+}
+
+func TestPageIteratorErrorKeepsPosition(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	fail := true
+
+	mux.HandleFunc("/uri", func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		fmt.Fprintf(w, `{"size":1,"collection":[{"id":"%s"}]}`, r.URL.Query().Get("offset"))
+	})
+
+	iterator := NewPageIterator(client, ctx, "/uri", nil)
+	page := new(Page[item])
+
+	if err := iterator.Next(page); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	fail = false
+
+	if err := iterator.Next(page); err != nil || page.Collection[0].Id != "0" {
+		t.Errorf("Expected first page after error, given: %v %+v", err, page)
+	}
+}
+
+func TestPageIteratorPrefetch(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	requested := make(chan string, 10)
+
+	mux.HandleFunc("/uri", func(w http.ResponseWriter, r *http.Request) {
+		offset := r.URL.Query().Get("offset")
+		requested <- offset
+
+		fmt.Fprintf(w, `{"size":4,"collection":[{"id":"%s"},{"id":"x"}]}`, offset)
+	})
+
+	iterator := NewPageIterator(client, ctx, "/uri", &PaginationFilters{Limit: 2})
+	iterator.Prefetch = true
+
+	page := new(Page[item])
+
+	if err := iterator.Next(page); err != nil {
+		t.Fatal(err)
+	}
+
+	<-requested
+
+	select {
+	case offset := <-requested:
+		if offset != "2" {
+			t.Errorf("Expected prefetch of offset 2, given: %s", offset)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the next page to be prefetched")
+	}
+
+	if err := iterator.Next(page); err != nil || page.Collection[0].Id != "2" {
+		t.Errorf("Expected prefetched page, given: %v %+v", err, page)
+	}
+
+	if err := iterator.Next(page); err != NoMoreResults || len(requested) != 0 {
+		t.Errorf("Expected NoMoreResults without further requests, given: %v", err)
+	}
+}
+
+func TestPageIteratorConcurrentNext(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var calls []string
+
+	mux.HandleFunc("/uri", pageServer{size: 50}.handler(&calls))
+
+	iterator := NewPageIterator(client, ctx, "/uri", &PaginationFilters{Limit: 5})
+	iterator.Prefetch = true
+
+	var mu sync.Mutex
+	seen := map[string]int{}
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				page := new(Page[item])
+
+				if err := iterator.Next(page); err != nil {
+					return
+				}
+
+				mu.Lock()
+				for _, i := range page.Collection {
+					seen[i.Id]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(seen) != 50 {
+		t.Errorf("Expected 50 distinct items, given: %d", len(seen))
+	}
+
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("Item %s seen %d times", id, count)
+		}
+	}
+}
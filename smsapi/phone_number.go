@@ -0,0 +1,162 @@
+package smsapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/smsapi/smsapi-go/smsapi/phonenumber"
+)
+
+// PhoneNumberPolicy enables client side validation of phone numbers. Numbers of sent
+// messages, contacts, blacklist entries and MFA codes are normalised to the SMSAPI format
+// and invalid ones are rejected with InvalidPhoneNumberError before the request is made.
+//
+// Request fields such as Sms.To, Contact.PhoneNumber, BlackListPhoneNumber.PhoneNumber and
+// CreateMfaCode.PhoneNumber remain strings, as Sms.To holds comma separated lists and changing
+// their types would break existing code. They are set and read as phonenumber.PhoneNumber
+// with SetTo / ParsedTo and SetPhoneNumber / ParsedPhoneNumber.
+type PhoneNumberPolicy struct {
+	// DefaultRegion is the region of numbers given in national format, e.g. "PL".
+	// Empty means numbers are expected in international format.
+	DefaultRegion string
+}
+
+// WithPhoneNumberPolicy enables client side validation of phone numbers, see PhoneNumberPolicy.
+func WithPhoneNumberPolicy(policy *PhoneNumberPolicy) Option {
+	return func(o *options) error {
+		if policy != nil && policy.DefaultRegion != "" {
+			if _, ok := phonenumber.CountryCodeForRegion(policy.DefaultRegion); !ok {
+				return fmt.Errorf("%w: %s", phonenumber.ErrUnknownRegion, policy.DefaultRegion)
+			}
+		}
+
+		o.phoneNumberPolicy = policy
+
+		return nil
+	}
+}
+
+type InvalidPhoneNumberError struct {
+	PhoneNumber string
+	Err         error
+}
+
+func (e *InvalidPhoneNumberError) Error() string {
+	return fmt.Sprintf("smsapi: invalid phone number %q: %s", e.PhoneNumber, e.Err)
+}
+
+func (e *InvalidPhoneNumberError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches ErrInvalidRecipient, as the API would reject the number.
+func (e *InvalidPhoneNumberError) Is(target error) bool {
+	return target == ErrInvalidRecipient
+}
+
+// normalizePhoneNumber returns the number in the SMSAPI format when PhoneNumberPolicy is set.
+// Empty numbers are left to the API.
+func (c *Client) normalizePhoneNumber(number string) (string, error) {
+	if c.PhoneNumberPolicy == nil || number == "" {
+		return number, nil
+	}
+
+	parsed, err := phonenumber.Parse(number, c.PhoneNumberPolicy.DefaultRegion)
+
+	if err != nil {
+		return number, &InvalidPhoneNumberError{PhoneNumber: number, Err: err}
+	}
+
+	return parsed.String(), nil
+}
+
+// defaultRegion returns the region of national numbers, empty without PhoneNumberPolicy.
+func (c *Client) defaultRegion() string {
+	if c.PhoneNumberPolicy == nil {
+		return ""
+	}
+
+	return c.PhoneNumberPolicy.DefaultRegion
+}
+
+// normalizePhoneNumbers is normalizePhoneNumber for a comma separated list of numbers.
+func (c *Client) normalizePhoneNumbers(numbers string) (string, error) {
+	if c.PhoneNumberPolicy == nil || numbers == "" {
+		return numbers, nil
+	}
+
+	list := strings.Split(numbers, ",")
+
+	for i, number := range list {
+		normalized, err := c.normalizePhoneNumber(strings.TrimSpace(number))
+
+		if err != nil {
+			return numbers, err
+		}
+
+		list[i] = normalized
+	}
+
+	return strings.Join(list, ","), nil
+}
+
+// SetTo sets the recipients of the message in the SMSAPI format.
+func (sms *Sms) SetTo(numbers ...phonenumber.PhoneNumber) {
+	list := make([]string, len(numbers))
+
+	for i, number := range numbers {
+		list[i] = number.String()
+	}
+
+	sms.To = strings.Join(list, ",")
+}
+
+// ParsedTo returns the recipients of the message. Numbers in national format, or otherwise
+// invalid, are kept as given, see phonenumber.PhoneNumber.IsValid.
+func (sms *Sms) ParsedTo() []phonenumber.PhoneNumber {
+	var result []phonenumber.PhoneNumber
+
+	for _, number := range strings.Split(sms.To, ",") {
+		if parsed := decodePhoneNumber(number); !parsed.IsZero() {
+			result = append(result, parsed)
+		}
+	}
+
+	return result
+}
+
+func (contact *Contact) SetPhoneNumber(number phonenumber.PhoneNumber) {
+	contact.PhoneNumber = number.String()
+}
+
+// ParsedPhoneNumber returns the phone number, see Sms.ParsedTo.
+func (contact *Contact) ParsedPhoneNumber() phonenumber.PhoneNumber {
+	return decodePhoneNumber(contact.PhoneNumber)
+}
+
+func (entry *BlackListPhoneNumber) SetPhoneNumber(number phonenumber.PhoneNumber) {
+	entry.PhoneNumber = number.String()
+}
+
+// ParsedPhoneNumber returns the phone number, see Sms.ParsedTo.
+func (entry *BlackListPhoneNumber) ParsedPhoneNumber() phonenumber.PhoneNumber {
+	return decodePhoneNumber(entry.PhoneNumber)
+}
+
+func (code *CreateMfaCode) SetPhoneNumber(number phonenumber.PhoneNumber) {
+	code.PhoneNumber = number.String()
+}
+
+// ParsedPhoneNumber returns the phone number, see Sms.ParsedTo.
+func (code *CreateMfaCode) ParsedPhoneNumber() phonenumber.PhoneNumber {
+	return decodePhoneNumber(code.PhoneNumber)
+}
+
+// decodePhoneNumber parses the number leniently, as phone numbers of API responses.
+func decodePhoneNumber(number string) phonenumber.PhoneNumber {
+	var result phonenumber.PhoneNumber
+
+	_ = result.UnmarshalText([]byte(number))
+
+	return result
+}
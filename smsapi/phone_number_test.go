@@ -0,0 +1,130 @@
+package smsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/smsapi/smsapi-go/smsapi/phonenumber"
+)
+
+func TestPhoneNumberPolicyNormalizesNumbers(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	client.PhoneNumberPolicy = &PhoneNumberPolicy{DefaultRegion: "PL"}
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		sms := new(Sms)
+		json.NewDecoder(r.Body).Decode(sms)
+
+		if sms.To != "48500600700,48500600701" {
+			t.Errorf("Expected normalized numbers, given: %s", sms.To)
+		}
+
+		fmt.Fprint(w, `{"count":0,"list":[]}`)
+	})
+
+	mux.HandleFunc("/contacts", func(w http.ResponseWriter, r *http.Request) {
+		assertRequestUrlencoded(t, r, &Contact{PhoneNumber: "48500600700"})
+		fmt.Fprint(w, `{}`)
+	})
+
+	sms := &Sms{To: "500 600 700, +48 500 600 701"}
+
+	if _, err := client.Sms.SendRaw(ctx, sms); err != nil {
+		t.Fatal(err)
+	}
+
+	if sms.To != "500 600 700, +48 500 600 701" {
+		t.Errorf("Expected sms to be left unchanged, given: %s", sms.To)
+	}
+
+	if _, err := client.Contacts.CreateContact(ctx, &Contact{PhoneNumber: "500-600-700"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPhoneNumberPolicyRejectsInvalidNumbers(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	client.PhoneNumberPolicy = &PhoneNumberPolicy{DefaultRegion: "PL"}
+
+	_, err := client.Sms.Send(ctx, "500600700,12", "test", "")
+
+	var invalid *InvalidPhoneNumberError
+
+	if !errors.As(err, &invalid) || invalid.PhoneNumber != "12" || !errors.Is(err, ErrInvalidRecipient) || !errors.Is(err, phonenumber.ErrInvalidLength) {
+		t.Errorf("Expected InvalidPhoneNumberError, given: %v", err)
+	}
+
+	if _, err := client.Blacklist.AddPhoneNumber(ctx, "abc", nil); !errors.Is(err, phonenumber.ErrInvalidCharacters) {
+		t.Errorf("Expected invalid blacklist number, given: %v", err)
+	}
+
+	if err := client.Mfa.VerifyCode(ctx, "", "123"); err != nil && errors.As(err, &invalid) {
+		t.Errorf("Expected empty number to be left to the API, given: %v", err)
+	}
+}
+
+func TestPhoneNumberPolicyInBulkSend(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	client.PhoneNumberPolicy = &PhoneNumberPolicy{DefaultRegion: "PL"}
+
+	requests := serveBulk(mux, sentList)
+
+	report, _ := client.Sms.SendBulk(ctx, []Recipient{{PhoneNumber: "500600700"}, {PhoneNumber: "12"}}, &Sms{Message: "test"}, nil)
+
+	if (*requests)[0].To != "48500600700" || len(report.Invalid) != 1 || report.Invalid[0].SubmittedNumber != "12" {
+		t.Errorf("Expected invalid number to be reported, given: %s %+v", (*requests)[0].To, report.Invalid)
+	}
+}
+
+func TestWithPhoneNumberPolicy(t *testing.T) {
+	if _, err := New(WithToken("t"), WithPhoneNumberPolicy(&PhoneNumberPolicy{DefaultRegion: "XX"})); !errors.Is(err, phonenumber.ErrUnknownRegion) {
+		t.Errorf("Expected unknown region error, given: %v", err)
+	}
+
+	client, _ := New(WithToken("t"), WithPhoneNumberPolicy(&PhoneNumberPolicy{DefaultRegion: "pl"}))
+
+	if client.PhoneNumberPolicy.DefaultRegion != "pl" {
+		t.Errorf("Expected policy to be set")
+	}
+}
+
+func TestTypedPhoneNumbers(t *testing.T) {
+	first := phonenumber.MustParse("500 600 700", "PL")
+	second := phonenumber.MustParse("+49 151 2345678", "")
+
+	sms := &Sms{}
+	sms.SetTo(first, second)
+
+	if sms.To != "48500600700,491512345678" {
+		t.Errorf("Unexpected recipients: %s", sms.To)
+	}
+
+	parsed := sms.ParsedTo()
+
+	if len(parsed) != 2 || parsed[0] != first || parsed[1].Region() != "DE" {
+		t.Errorf("Unexpected parsed recipients: %v", parsed)
+	}
+
+	contact := &Contact{}
+	contact.SetPhoneNumber(first)
+
+	entry := &BlackListPhoneNumber{PhoneNumber: "+48 500 600 700"}
+	code := &CreateMfaCode{PhoneNumber: "800123"}
+
+	if contact.PhoneNumber != "48500600700" || entry.ParsedPhoneNumber() != first {
+		t.Errorf("Unexpected phone numbers: %s %v", contact.PhoneNumber, entry.ParsedPhoneNumber())
+	}
+
+	if number := code.ParsedPhoneNumber(); number.IsValid() || number.String() != "800123" {
+		t.Errorf("Expected invalid number to be kept as given, given: %v", number)
+	}
+}
@@ -0,0 +1,294 @@
+// Package phonenumber parses phone numbers given in national or international format
+// and normalises them to E.164 and the SMSAPI format, i.e. E.164 without the leading "+".
+//
+// The numbering plan knowledge is limited to country calling codes and national number
+// lengths of common regions, numbers are not checked against allocated ranges.
+package phonenumber
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxDigits is the maximum length of an E.164 number, without the "+".
+	maxDigits         = 15
+	minNationalDigits = 4
+)
+
+var (
+	ErrEmpty              = errors.New("phonenumber: empty phone number")
+	ErrInvalidCharacters  = errors.New("phonenumber: phone number contains invalid characters")
+	ErrUnknownCountryCode = errors.New("phonenumber: unknown country calling code")
+	ErrUnknownRegion      = errors.New("phonenumber: unknown region")
+	ErrInvalidLength      = errors.New("phonenumber: invalid phone number length")
+)
+
+// PhoneNumber is a parsed phone number. The zero value is an empty number, it is omitted
+// from query parameters and marshalled to an empty JSON string.
+//
+// Numbers decoded from JSON or text are not validated, as the API may return numbers this
+// package cannot parse. Such numbers are kept as received, see IsValid.
+type PhoneNumber struct {
+	countryCode string
+	national    string
+	// raw is a decoded number that could not be parsed.
+	raw string
+}
+
+// Parse parses a phone number. Numbers starting with "+" or "00" are international.
+// Other numbers are national numbers of defaultRegion (an ISO 3166-1 alpha-2 code, e.g. "PL"),
+// unless they start with the country code of the region and have the length of an
+// international number. Without defaultRegion numbers are expected in the SMSAPI format.
+// Spaces, dashes, dots, slashes and parentheses are ignored.
+func Parse(number, defaultRegion string) (PhoneNumber, error) {
+	number = strings.TrimSpace(number)
+
+	if number == "" {
+		return PhoneNumber{}, ErrEmpty
+	}
+
+	international := strings.HasPrefix(number, "+")
+
+	digits, err := stripFormatting(strings.TrimPrefix(number, "+"))
+
+	if err != nil {
+		return PhoneNumber{}, err
+	}
+
+	if !international && strings.HasPrefix(digits, "00") {
+		international, digits = true, digits[2:]
+	}
+
+	region := strings.ToUpper(defaultRegion)
+
+	if !international && region != "" {
+		code, ok := regionCodes[region]
+
+		if !ok {
+			return PhoneNumber{}, fmt.Errorf("%w: %s", ErrUnknownRegion, defaultRegion)
+		}
+
+		if isNational(region, code, digits) {
+			return newPhoneNumber(code, strings.TrimPrefix(digits, trunkPrefix(region)))
+		}
+	}
+
+	for size := 3; size > 0; size-- {
+		if len(digits) <= size {
+			continue
+		}
+
+		if _, ok := countryCodes[digits[:size]]; ok {
+			return newPhoneNumber(digits[:size], digits[size:])
+		}
+	}
+
+	return PhoneNumber{}, ErrUnknownCountryCode
+}
+
+// MustParse is like Parse but panics on invalid numbers. It simplifies initialisation
+// of numbers known to be valid, e.g. in tests.
+func MustParse(number, defaultRegion string) PhoneNumber {
+	n, err := Parse(number, defaultRegion)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return n
+}
+
+func newPhoneNumber(code, national string) (PhoneNumber, error) {
+	n := PhoneNumber{countryCode: code, national: national}
+
+	if !validLength(n.Region(), code, national) {
+		return PhoneNumber{}, ErrInvalidLength
+	}
+
+	return n, nil
+}
+
+// isNational tells whether digits given without an international prefix are a national
+// number of the region or an international number in the SMSAPI format.
+func isNational(region, code, digits string) bool {
+	if !strings.HasPrefix(digits, code) {
+		return true
+	}
+
+	prefix := trunkPrefix(region)
+
+	if _, ok := nationalLengths[region]; ok {
+		national := strings.TrimPrefix(digits, prefix)
+
+		return validLength(region, code, national) || !validLength(region, code, digits[len(code):])
+	}
+
+	// Without known lengths only numbers dialled with the trunk prefix are taken as national.
+	return prefix != "" && strings.HasPrefix(digits, prefix)
+}
+
+func stripFormatting(number string) (string, error) {
+	var b strings.Builder
+
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case strings.ContainsRune(" -./()", r):
+		default:
+			return "", ErrInvalidCharacters
+		}
+	}
+
+	return b.String(), nil
+}
+
+func validLength(region, code, national string) bool {
+	if lengths, ok := nationalLengths[region]; ok {
+		return len(national) >= lengths[0] && len(national) <= lengths[1]
+	}
+
+	return len(national) >= minNationalDigits && len(code)+len(national) <= maxDigits
+}
+
+// CountryCodeForRegion returns the country calling code of a region, e.g. 48 for "PL".
+func CountryCodeForRegion(region string) (int, bool) {
+	code, ok := regionCodes[strings.ToUpper(region)]
+
+	if !ok {
+		return 0, false
+	}
+
+	value, _ := strconv.Atoi(code)
+
+	return value, true
+}
+
+// CountryCode returns the country calling code, e.g. 48.
+func (n PhoneNumber) CountryCode() int {
+	code, _ := strconv.Atoi(n.countryCode)
+
+	return code
+}
+
+// Region returns the ISO 3166-1 alpha-2 code of the region, e.g. "PL". Numbers of regions
+// sharing the country code may be reported as the most populous one, e.g. Canadian as "US".
+func (n PhoneNumber) Region() string {
+	for prefix, region := range sharedCodeRegions[n.countryCode] {
+		if strings.HasPrefix(n.national, prefix) {
+			return region
+		}
+	}
+
+	return countryCodes[n.countryCode]
+}
+
+// NationalNumber returns the number without the country code and trunk prefix.
+func (n PhoneNumber) NationalNumber() string {
+	return n.national
+}
+
+// E164 returns the number in E.164 format, e.g. "+48500600700", or an empty string for
+// invalid numbers.
+func (n PhoneNumber) E164() string {
+	if !n.IsValid() {
+		return ""
+	}
+
+	return "+" + n.String()
+}
+
+// String returns the number in the SMSAPI format, e.g. "48500600700". Invalid numbers
+// are returned as received.
+func (n PhoneNumber) String() string {
+	if n.raw != "" {
+		return n.raw
+	}
+
+	return n.countryCode + n.national
+}
+
+// Int64 returns the number in the SMSAPI format as an integer.
+func (n PhoneNumber) Int64() int64 {
+	value, _ := strconv.ParseInt(n.String(), 10, 64)
+
+	return value
+}
+
+func (n PhoneNumber) IsZero() bool {
+	return n.countryCode == "" && n.raw == ""
+}
+
+// IsValid reports whether the number was parsed. Numbers decoded from JSON or text
+// the package could not parse only keep their String value.
+func (n PhoneNumber) IsValid() bool {
+	return n.countryCode != ""
+}
+
+func (n PhoneNumber) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// UnmarshalText parses international numbers, an empty text gives the zero value.
+// Numbers that cannot be parsed are kept as received, digits only when possible.
+func (n *PhoneNumber) UnmarshalText(text []byte) error {
+	value := strings.TrimSpace(string(text))
+
+	if value == "" {
+		*n = PhoneNumber{}
+
+		return nil
+	}
+
+	parsed, err := Parse(value, "")
+
+	if err != nil {
+		if digits, err := stripFormatting(strings.TrimPrefix(value, "+")); err == nil && digits != "" {
+			value = digits
+		}
+
+		parsed = PhoneNumber{raw: value}
+	}
+
+	*n = parsed
+
+	return nil
+}
+
+func (n PhoneNumber) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.String())
+}
+
+// UnmarshalJSON accepts numbers encoded as JSON strings and numbers, as the API uses both.
+func (n *PhoneNumber) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var text string
+
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	} else {
+		text = string(data)
+	}
+
+	return n.UnmarshalText([]byte(text))
+}
+
+// EncodeValues implements query.Encoder of github.com/google/go-querystring,
+// zero numbers are omitted.
+func (n PhoneNumber) EncodeValues(key string, v *url.Values) error {
+	if !n.IsZero() {
+		v.Add(key, n.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package phonenumber
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-querystring/query"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		number   string
+		region   string
+		expected string
+		country  string
+	}{
+		{"+48 500 600 700", "", "48500600700", "PL"},
+		{"0048500600700", "", "48500600700", "PL"},
+		{"48500600700", "", "48500600700", "PL"},
+		{"500-600-700", "PL", "48500600700", "PL"},
+		{"48500600700", "pl", "48500600700", "PL"},
+		{"485006007", "PL", "48485006007", "PL"},
+		{"0151 23456789", "DE", "4915123456789", "DE"},
+		{"4915123456789", "DE", "4915123456789", "DE"},
+		{"(202) 555-0100", "US", "12025550100", "US"},
+		{"1 202 555 0100", "US", "12025550100", "US"},
+		{"3331234567", "IT", "393331234567", "IT"},
+		{"+7 701 123 4567", "", "77011234567", "KZ"},
+		{"8 912 123 4567", "RU", "79121234567", "RU"},
+		{"+420 601 123 456", "", "420601123456", "CZ"},
+	}
+
+	for _, test := range tests {
+		given, err := Parse(test.number, test.region)
+
+		if err != nil {
+			t.Errorf("%s: %v", test.number, err)
+
+			continue
+		}
+
+		if given.String() != test.expected || given.Region() != test.country {
+			t.Errorf("%s expected: %s %s given: %s %s", test.number, test.expected, test.country, given, given.Region())
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		number   string
+		region   string
+		expected error
+	}{
+		{" ", "", ErrEmpty},
+		{"48500a600700", "", ErrInvalidCharacters},
+		{"+800600700", "", ErrUnknownCountryCode},
+		{"500600700", "XX", ErrUnknownRegion},
+		{"+48 500 600 70", "", ErrInvalidLength},
+		{"50060070", "PL", ErrInvalidLength},
+	}
+
+	for _, test := range tests {
+		if _, err := Parse(test.number, test.region); !errors.Is(err, test.expected) {
+			t.Errorf("%q expected: %v given: %v", test.number, test.expected, err)
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	n := MustParse("500600700", "PL")
+
+	if n.E164() != "+48500600700" || n.CountryCode() != 48 || n.NationalNumber() != "500600700" || n.Int64() != 48500600700 {
+		t.Errorf("Unexpected formats of %s", n)
+	}
+
+	if code, ok := CountryCodeForRegion("kz"); !ok || code != 7 {
+		t.Errorf("Unexpected KZ country code: %d", code)
+	}
+
+	if (PhoneNumber{}).E164() != "" || !(PhoneNumber{}).IsZero() {
+		t.Error("Expected empty zero value")
+	}
+}
+
+func TestJson(t *testing.T) {
+	var given struct {
+		FromString PhoneNumber `json:"from_string"`
+		FromNumber PhoneNumber `json:"from_number"`
+		Empty      PhoneNumber `json:"empty"`
+	}
+
+	err := json.Unmarshal([]byte(`{"from_string":"+48500600700","from_number":48500600700,"empty":""}`), &given)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if given.FromString.String() != "48500600700" || given.FromNumber != given.FromString || !given.Empty.IsZero() {
+		t.Errorf("Unexpected numbers: %+v", given)
+	}
+
+	encoded, _ := json.Marshal(given.FromNumber)
+
+	if string(encoded) != `"48500600700"` {
+		t.Errorf("Unexpected JSON: %s", encoded)
+	}
+
+	var unknown PhoneNumber
+
+	if err := json.Unmarshal([]byte(`800123`), &unknown); err != nil {
+		t.Fatal(err)
+	}
+
+	if unknown.IsValid() || unknown.IsZero() || unknown.String() != "800123" || unknown.E164() != "" || unknown.Int64() != 800123 {
+		t.Errorf("Expected the unparsed number to be kept, given: %+v", unknown)
+	}
+
+	if err := json.Unmarshal([]byte(`"abc"`), &unknown); err != nil || unknown.String() != "abc" {
+		t.Errorf("Expected the unparsed number to be kept, given: %+v %v", unknown, err)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	filters := struct {
+		PhoneNumber PhoneNumber `url:"phone_number,omitempty"`
+		Other       PhoneNumber `url:"other,omitempty"`
+	}{PhoneNumber: MustParse("+48500600700", "")}
+
+	given, _ := query.Values(filters)
+
+	if given.Encode() != (url.Values{"phone_number": {"48500600700"}}).Encode() {
+		t.Errorf("Unexpected query: %s", given.Encode())
+	}
+}
@@ -22,10 +22,12 @@ type ProfileDetailsResponse struct {
 	Points      Points `json:"points,omitempty"`
 }
 
-func (accountApi *ProfileApi) Details(ctx context.Context) (*ProfileDetailsResponse, error) {
+func (accountApi *ProfileApi) Details(ctx context.Context, opts ...CallOption) (*ProfileDetailsResponse, error) {
+	ctx = withOperation(ctx, "ProfileApi.Details")
+
 	var result = new(ProfileDetailsResponse)
 
-	err := accountApi.client.Get(ctx, profileApiPath, result)
+	err := accountApi.client.Get(ctx, profileApiPath, result, opts...)
 
 	return result, err
 }
@@ -53,12 +55,14 @@ type ProfilePricesFilters struct {
 
 // Prices returns SMS/VMS/etc. pricing for this profile. `pricingType` selects the
 // service (e.g. "pro", "eco", "sms", "2way", "vms", "hlr", "mms"); pass empty string for all.
-func (accountApi *ProfileApi) Prices(ctx context.Context, pricingType string) (*ProfilePricesResponse, error) {
+func (accountApi *ProfileApi) Prices(ctx context.Context, pricingType string, opts ...CallOption) (*ProfilePricesResponse, error) {
+	ctx = withOperation(ctx, "ProfileApi.Prices")
+
 	var result = new(ProfilePricesResponse)
 
 	uri, _ := addQueryParams("/profile/prices", &ProfilePricesFilters{Type: pricingType})
 
-	err := accountApi.client.Get(ctx, uri, result)
+	err := accountApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
@@ -0,0 +1,197 @@
+// Package promsmsapi exposes smsapi.Client usage and spend as Prometheus metrics.
+//
+//	collector := promsmsapi.NewCollector(client)
+//	prometheus.MustRegister(collector)
+//	go collector.PollBalance(ctx, time.Minute)
+package promsmsapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/smsapi/smsapi-go/smsapi"
+	"github.com/smsapi/smsapi-go/smsapi/internal/callstats"
+)
+
+const (
+	Namespace = "smsapi"
+
+	mfaVerificationsPath = "/mfa/codes/verifications"
+)
+
+// Collector counts calls made by the client it was created for.
+type Collector struct {
+	client *smsapi.Client
+
+	requests         *prometheus.CounterVec
+	errors           *prometheus.CounterVec
+	messages         *prometheus.CounterVec
+	parts            *prometheus.CounterVec
+	points           *prometheus.CounterVec
+	hlrLookups       prometheus.Counter
+	mfaVerifications *prometheus.CounterVec
+	balance          prometheus.Gauge
+}
+
+// NewCollector creates a collector and registers its middleware on the client.
+// Register the collector with a prometheus.Registerer to expose the metrics.
+func NewCollector(client *smsapi.Client) *Collector {
+	c := &Collector{
+		client: client,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "requests_total",
+			Help:      "Number of SMSAPI calls by area.",
+		}, []string{"area"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "errors_total",
+			Help:      "Number of failed SMSAPI calls by area and error code.",
+		}, []string{"area", "code"}),
+		messages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "messages_sent_total",
+			Help:      "Number of messages accepted for sending by channel.",
+		}, []string{"channel"}),
+		parts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "message_parts_total",
+			Help:      "Number of message parts accepted for sending by channel.",
+		}, []string{"channel"}),
+		points: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "points_spent_total",
+			Help:      "Points spent on sent messages and HLR lookups by area.",
+		}, []string{"area"}),
+		hlrLookups: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "hlr_lookups_total",
+			Help:      "Number of successful HLR lookups.",
+		}),
+		mfaVerifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "mfa_verifications_total",
+			Help:      "Number of MFA code verifications by result.",
+		}, []string{"result"}),
+		balance: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "points_balance",
+			Help:      "Remaining points of the account, updated by PollBalance.",
+		}),
+	}
+
+	client.Use(c.middleware)
+
+	return c
+}
+
+func (c *Collector) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		c.requests,
+		c.errors,
+		c.messages,
+		c.parts,
+		c.points,
+		c.hlrLookups,
+		c.mfaVerifications,
+		c.balance,
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range c.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range c.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+// PollBalance updates the balance gauge from ProfileApi.Details right away and then
+// every interval, until the context is done.
+func (c *Collector) PollBalance(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.UpdateBalance(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// UpdateBalance fetches the account points once. On error the gauge keeps its last value.
+func (c *Collector) UpdateBalance(ctx context.Context) error {
+	details, err := c.client.Profile.Details(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	c.balance.Set(float64(details.Points))
+
+	return nil
+}
+
+func (c *Collector) middleware(next smsapi.RoundTripFunc) smsapi.RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		resp, err := next(req)
+
+		info, ok := smsapi.RequestInfoFromContext(req.Context())
+
+		if !ok {
+			return resp, err
+		}
+
+		area := string(info.Area)
+		result := callstats.Inspect(req, resp, err, info.Area)
+
+		c.requests.WithLabelValues(area).Inc()
+
+		if isMfaVerification(info) {
+			if result.Failed {
+				c.mfaVerifications.WithLabelValues("failure").Inc()
+			} else {
+				c.mfaVerifications.WithLabelValues("success").Inc()
+			}
+		}
+
+		if result.Failed {
+			c.errors.WithLabelValues(area, result.ErrorCode).Inc()
+
+			return resp, err
+		}
+
+		if !result.Send {
+			return resp, err
+		}
+
+		switch info.Area {
+		case smsapi.AreaSms, smsapi.AreaMms, smsapi.AreaVms:
+			c.messages.WithLabelValues(area).Add(float64(result.Messages))
+			c.parts.WithLabelValues(area).Add(float64(result.Parts))
+		case smsapi.AreaHlr:
+			c.hlrLookups.Add(float64(result.Messages))
+		}
+
+		if result.Points > 0 {
+			c.points.WithLabelValues(area).Add(result.Points)
+		}
+
+		return resp, err
+	}
+}
+
+func isMfaVerification(info *smsapi.RequestInfo) bool {
+	return info.Method == http.MethodPost && strings.TrimSuffix(info.Path, "/") == mfaVerificationsPath
+}
@@ -0,0 +1,178 @@
+package promsmsapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/smsapi/smsapi-go/smsapi"
+)
+
+var ctx = context.Background()
+
+func setup(t *testing.T) (*smsapi.Client, *http.ServeMux, *Collector, func()) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	client, err := smsapi.New(smsapi.WithBaseURL(server.URL+"/"), smsapi.WithToken("token"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client, mux, NewCollector(client), server.Close
+}
+
+func TestCollectorCountsSends(t *testing.T) {
+	client, mux, collector, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"count":2,"list":[{"id":"1","points":0.25,"parts":2},{"id":"2","points":0.25,"parts":1}]}`)
+	})
+
+	mux.HandleFunc("/vms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"count":1,"list":[{"id":"3","points":1.5}]}`)
+	})
+
+	client.Sms.Send(ctx, "48100200300,48100200301", "test", "")
+	client.Vms.Send(ctx, "48100200300", "test", "")
+
+	expected := `
+# HELP smsapi_messages_sent_total Number of messages accepted for sending by channel.
+# TYPE smsapi_messages_sent_total counter
+smsapi_messages_sent_total{channel="sms"} 2
+smsapi_messages_sent_total{channel="vms"} 1
+# HELP smsapi_message_parts_total Number of message parts accepted for sending by channel.
+# TYPE smsapi_message_parts_total counter
+smsapi_message_parts_total{channel="sms"} 3
+smsapi_message_parts_total{channel="vms"} 0
+# HELP smsapi_points_spent_total Points spent on sent messages and HLR lookups by area.
+# TYPE smsapi_points_spent_total counter
+smsapi_points_spent_total{area="sms"} 0.5
+smsapi_points_spent_total{area="vms"} 1.5
+`
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"smsapi_messages_sent_total", "smsapi_message_parts_total", "smsapi_points_spent_total")
+
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollectorIgnoresStatusLookups(t *testing.T) {
+	client, mux, collector, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"count":1,"list":[{"id":"1","points":0.16,"parts":1,"status":"DELIVERED"}]}`)
+	})
+
+	for range 3 {
+		if _, err := client.Sms.Get(ctx, "1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := client.Sms.RemoveScheduled(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, metric := range []string{"smsapi_messages_sent_total", "smsapi_message_parts_total", "smsapi_points_spent_total"} {
+		if count := testutil.CollectAndCount(collector, metric); count != 0 {
+			t.Errorf("Expected no %s series, given: %d", metric, count)
+		}
+	}
+}
+
+func TestCollectorCountsErrorsByCode(t *testing.T) {
+	client, mux, collector, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"error":103,"message":"Not enough points"}`)
+	})
+
+	client.Sms.Send(ctx, "48100200300", "test", "")
+	client.Sms.Send(ctx, "48100200300", "test", "")
+
+	if given := testutil.ToFloat64(collector.errors.WithLabelValues("sms", "103")); given != 2 {
+		t.Errorf("Expected 2 errors, given: %v", given)
+	}
+
+	if given := testutil.ToFloat64(collector.messages.WithLabelValues("sms")); given != 0 {
+		t.Errorf("Expected no messages, given: %v", given)
+	}
+}
+
+func TestCollectorCountsHlrAndMfa(t *testing.T) {
+	client, mux, collector, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/hlr.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"OK","number":"48100200300","id":"1","price":0.02}`)
+	})
+
+	mux.HandleFunc("/mfa/codes/verifications", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		if r.PostForm.Get("code") != "123456" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"not_found","message":"Code not found"}`)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client.Hlr.CheckNumber(ctx, "48100200300")
+	client.Mfa.VerifyCode(ctx, "48100200300", "123456")
+	client.Mfa.VerifyCode(ctx, "48100200300", "000000")
+
+	if given := testutil.ToFloat64(collector.hlrLookups); given != 1 {
+		t.Errorf("Expected 1 HLR lookup, given: %v", given)
+	}
+
+	if given := testutil.ToFloat64(collector.points.WithLabelValues("hlr")); given < 0.0199 || given > 0.0201 {
+		t.Errorf("Expected 0.02 points, given: %v", given)
+	}
+
+	success := testutil.ToFloat64(collector.mfaVerifications.WithLabelValues("success"))
+	failure := testutil.ToFloat64(collector.mfaVerifications.WithLabelValues("failure"))
+
+	if success != 1 || failure != 1 {
+		t.Errorf("Expected 1 successful and 1 failed verification, given: %v %v", success, failure)
+	}
+}
+
+func TestCollectorUpdateBalance(t *testing.T) {
+	_, mux, collector, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"username":"test","points":"123.5000"}`)
+	})
+
+	if err := collector.UpdateBalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if given := testutil.ToFloat64(collector.balance); given != 123.5 {
+		t.Errorf("Expected 123.5 points, given: %v", given)
+	}
+}
+
+func TestCollectorRegisters(t *testing.T) {
+	_, _, collector, teardown := setup(t)
+	defer teardown()
+
+	if err := prometheus.NewPedanticRegistry().Register(collector); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,231 @@
+package smsapi
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const globalBucketName = "global"
+
+// RateLimit describes a token bucket: Rate tokens per second refilled up to Burst.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// BucketState is a point in time view of a single token bucket.
+type BucketState struct {
+	// Name is the ApiArea of the bucket or "global".
+	Name        string
+	Rate        float64
+	Burst       int
+	Tokens      float64
+	PausedUntil time.Time
+}
+
+// RateLimiter throttles requests on the client side using a global token bucket
+// and optional buckets per API area (e.g. AreaSms for /sms.do, AreaContacts).
+// A request has to acquire a token from both the global and its area bucket.
+type RateLimiter struct {
+	mu      sync.Mutex
+	global  *bucket
+	buckets map[ApiArea]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	limit       RateLimit
+	tokens      float64
+	updated     time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter creates a limiter with the given global limit. Zero Rate means the
+// global bucket is unlimited and only area limits apply.
+func NewRateLimiter(global RateLimit) *RateLimiter {
+	l := &RateLimiter{
+		buckets: map[ApiArea]*bucket{},
+		now:     time.Now,
+	}
+
+	l.global = l.newBucket(global)
+
+	return l
+}
+
+// SetLimit configures a dedicated bucket for the given API area.
+func (l *RateLimiter) SetLimit(area ApiArea, limit RateLimit) *RateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buckets[area] = l.newBucket(limit)
+
+	return l
+}
+
+func (l *RateLimiter) newBucket(limit RateLimit) *bucket {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+
+	return &bucket{
+		limit:   limit,
+		tokens:  float64(limit.Burst),
+		updated: l.now(),
+	}
+}
+
+// Wait blocks until a request to the given area is allowed or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, area ApiArea) error {
+	for {
+		delay := l.reserve(area)
+
+		if delay == 0 {
+			return nil
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token from all involved buckets or returns how long to wait for it.
+func (l *RateLimiter) reserve(area ApiArea) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	buckets := []*bucket{l.global}
+
+	if b, ok := l.buckets[area]; ok {
+		buckets = append(buckets, b)
+	}
+
+	var delay time.Duration
+
+	for _, b := range buckets {
+		if d := b.delay(now); d > delay {
+			delay = d
+		}
+	}
+
+	if delay > 0 {
+		return delay
+	}
+
+	for _, b := range buckets {
+		b.take()
+	}
+
+	return 0
+}
+
+// Throttle pauses the area bucket, or the global one if the area has no dedicated
+// bucket, after the API responded with 429. Zero retryAfter drains the bucket instead.
+func (l *RateLimiter) Throttle(area ApiArea, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[area]
+
+	if !ok {
+		b = l.global
+	}
+
+	now := l.now()
+	b.refill(now)
+
+	if retryAfter <= 0 {
+		b.tokens = 0
+		return
+	}
+
+	if until := now.Add(retryAfter); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// State returns the current state of all buckets, the global one first.
+func (l *RateLimiter) State() []BucketState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	states := []BucketState{l.global.state(globalBucketName, now)}
+
+	for area, b := range l.buckets {
+		states = append(states, b.state(string(area), now))
+	}
+
+	sort.Slice(states[1:], func(i, j int) bool {
+		return states[i+1].Name < states[j+1].Name
+	})
+
+	return states
+}
+
+func (b *bucket) unlimited() bool {
+	return b.limit.Rate <= 0
+}
+
+func (b *bucket) refill(now time.Time) {
+	if b.unlimited() {
+		return
+	}
+
+	elapsed := now.Sub(b.updated).Seconds()
+
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+		b.updated = now
+	}
+}
+
+func (b *bucket) delay(now time.Time) time.Duration {
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+
+	if b.unlimited() {
+		return 0
+	}
+
+	b.refill(now)
+
+	if b.tokens >= 1 {
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second))
+}
+
+func (b *bucket) take() {
+	if !b.unlimited() {
+		b.tokens--
+	}
+}
+
+func (b *bucket) state(name string, now time.Time) BucketState {
+	b.refill(now)
+
+	return BucketState{
+		Name:        name,
+		Rate:        b.limit.Rate,
+		Burst:       b.limit.Burst,
+		Tokens:      b.tokens,
+		PausedUntil: b.pausedUntil,
+	}
+}
+
+// WithRateLimiter enables client side rate limiting, see RateLimiter.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(o *options) error {
+		o.rateLimiter = limiter
+
+		return nil
+	}
+}
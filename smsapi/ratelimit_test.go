@@ -0,0 +1,146 @@
+package smsapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func newTestRateLimiter(global RateLimit) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(1600000000, 0)}
+
+	l := NewRateLimiter(RateLimit{})
+	l.now = clock.now
+	l.global = l.newBucket(global)
+
+	return l, clock
+}
+
+func TestRateLimiterBurstAndRefill(t *testing.T) {
+	l, clock := newTestRateLimiter(RateLimit{Rate: 2, Burst: 2})
+
+	if l.reserve(AreaSms) != 0 || l.reserve(AreaSms) != 0 {
+		t.Fatal("Expected burst of 2 requests")
+	}
+
+	if d := l.reserve(AreaSms); d != 500*time.Millisecond {
+		t.Errorf("Expected 500ms delay, given: %v", d)
+	}
+
+	clock.t = clock.t.Add(500 * time.Millisecond)
+
+	if d := l.reserve(AreaSms); d != 0 {
+		t.Errorf("Expected refilled token, given delay: %v", d)
+	}
+}
+
+func TestRateLimiterAreaBuckets(t *testing.T) {
+	l, _ := newTestRateLimiter(RateLimit{})
+	l.SetLimit(AreaContacts, RateLimit{Rate: 1, Burst: 1})
+
+	l.reserve(AreaContacts)
+
+	if d := l.reserve(AreaContacts); d != time.Second {
+		t.Errorf("Expected contacts to be limited, given: %v", d)
+	}
+
+	if d := l.reserve(AreaSms); d != 0 {
+		t.Errorf("Expected sms to be unlimited, given: %v", d)
+	}
+}
+
+func TestRateLimiterThrottle(t *testing.T) {
+	l, clock := newTestRateLimiter(RateLimit{Rate: 100, Burst: 10})
+	l.SetLimit(AreaHlr, RateLimit{Rate: 100, Burst: 10})
+
+	l.Throttle(AreaHlr, 3*time.Second)
+
+	if d := l.reserve(AreaHlr); d != 3*time.Second {
+		t.Errorf("Expected hlr to be paused, given: %v", d)
+	}
+
+	if d := l.reserve(AreaSms); d != 0 {
+		t.Errorf("Expected sms not to be paused, given: %v", d)
+	}
+
+	l.Throttle(AreaSms, 0)
+
+	if d := l.reserve(AreaSms); d != 10*time.Millisecond {
+		t.Errorf("Expected global bucket to be drained, given: %v", d)
+	}
+
+	clock.t = clock.t.Add(3 * time.Second)
+
+	if d := l.reserve(AreaHlr); d != 0 {
+		t.Errorf("Expected hlr to be resumed, given: %v", d)
+	}
+}
+
+func TestRateLimiterState(t *testing.T) {
+	l, _ := newTestRateLimiter(RateLimit{Rate: 1, Burst: 5})
+	l.SetLimit(AreaSms, RateLimit{Rate: 1, Burst: 2})
+	l.SetLimit(AreaContacts, RateLimit{Rate: 1, Burst: 3})
+
+	l.reserve(AreaSms)
+
+	states := l.State()
+
+	if len(states) != 3 {
+		t.Fatalf("Expected 3 buckets, given: %+v", states)
+	}
+
+	expected := []struct {
+		name   string
+		tokens float64
+	}{{"global", 4}, {"contacts", 3}, {"sms", 1}}
+
+	for i, e := range expected {
+		if states[i].Name != e.name || states[i].Tokens != e.tokens {
+			t.Errorf("Expected: %+v Given: %+v", e, states[i])
+		}
+	}
+}
+
+func TestRateLimiterWaitRespectsContext(t *testing.T) {
+	l := NewRateLimiter(RateLimit{Rate: 0.001, Burst: 1})
+
+	l.Wait(ctx, AreaSms)
+
+	c, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(c, AreaSms); err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded, given: %v", err)
+	}
+}
+
+func TestClientRateLimiterThrottlesOnTooManyRequests(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/hlr.do", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{}`)
+	})
+
+	client.RateLimiter = NewRateLimiter(RateLimit{}).SetLimit(AreaHlr, RateLimit{Rate: 10, Burst: 10})
+
+	client.Hlr.CheckNumber(ctx, "48100200300")
+
+	states := client.RateLimiter.State()
+
+	if states[1].Name != "hlr" || time.Until(states[1].PausedUntil) < 29*time.Second {
+		t.Errorf("Expected hlr bucket to be paused, given: %+v", states)
+	}
+}
@@ -0,0 +1,210 @@
+package smsapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Response holds HTTP level metadata of an API call, see WithResponse.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	// RequestId is taken from the X-Request-Id response header, or from the
+	// request if the API did not echo it.
+	RequestId string
+	RateLimit RateLimitHeaders
+	Body      []byte
+	// Latency covers the whole call including retries and rate limiter waits.
+	Latency  time.Duration
+	Attempts int
+}
+
+// RateLimitHeaders are the X-RateLimit-* response headers, zero when not sent.
+type RateLimitHeaders struct {
+	Limit     int
+	Remaining int
+	Reset     int
+}
+
+// CallOption adjusts a single API call. Options are accepted by every API method and
+// can be attached to a context with ContextWithCallOptions, options given to the
+// method are applied last.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	response    *Response
+	timeout     time.Duration
+	header      http.Header
+	accessToken string
+	baseUrl     string
+}
+
+// WithCallTimeout limits the duration of the call including retries.
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithHeader adds a header to the request, replacing the value set by the client.
+func WithHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+
+		o.header.Set(key, value)
+	}
+}
+
+// WithAccessToken authorizes the call with another token, e.g. to act as a subuser.
+func WithAccessToken(accessToken string) CallOption {
+	return func(o *callOptions) {
+		o.accessToken = accessToken
+	}
+}
+
+// WithCallBaseURL sends the call to another API url, e.g. to reach the other region.
+func WithCallBaseURL(baseUrl string) CallOption {
+	return func(o *callOptions) {
+		o.baseUrl = baseUrl
+	}
+}
+
+type callOptionsKey struct{}
+
+// WithResponse captures response metadata of the call into the given Response.
+//
+//	var meta smsapi.Response
+//	result, err := client.Sms.Send(ctx, to, message, "", smsapi.WithResponse(&meta))
+func WithResponse(response *Response) CallOption {
+	return func(o *callOptions) {
+		o.response = response
+	}
+}
+
+// ContextWithCallOptions attaches call options to all API calls made with the returned context.
+func ContextWithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	o := callOptionsFromContext(ctx).clone()
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return context.WithValue(ctx, callOptionsKey{}, o)
+}
+
+func callOptionsFromContext(ctx context.Context) *callOptions {
+	if o, ok := ctx.Value(callOptionsKey{}).(*callOptions); ok {
+		return o
+	}
+
+	return &callOptions{}
+}
+
+func (o *callOptions) clone() *callOptions {
+	c := *o
+	c.header = o.header.Clone()
+
+	return &c
+}
+
+func (o *callOptions) with(opts []CallOption) *callOptions {
+	if len(opts) == 0 {
+		return o
+	}
+
+	c := o.clone()
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// apply adjusts the request, it returns the context the call must be made with.
+func (o *callOptions) apply(ctx context.Context, client *Client, req *http.Request) (context.Context, context.CancelFunc, error) {
+	cancel := context.CancelFunc(func() {})
+
+	if o.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+	}
+
+	for key, values := range o.header {
+		req.Header[key] = values
+	}
+
+	if o.accessToken != "" {
+		req.Header.Set("Authorization", (&BearerAuth{AccessToken: o.accessToken}).String())
+	}
+
+	if o.baseUrl != "" {
+		baseUrl, err := url.Parse(o.baseUrl)
+
+		if err != nil || !baseUrl.IsAbs() {
+			cancel()
+
+			return ctx, cancel, ErrInvalidBaseUrl
+		}
+
+		req.URL = rebase(req.URL, client.BaseUrl, baseUrl)
+		req.Host = baseUrl.Host
+	}
+
+	return ctx, cancel, nil
+}
+
+// rebase moves u from the from base url to the to base url.
+func rebase(u, from, to *url.URL) *url.URL {
+	c := *u
+	c.Scheme = to.Scheme
+	c.Host = to.Host
+	c.User = to.User
+
+	path := u.Path
+
+	if from != nil && strings.HasPrefix(path, from.Path) {
+		path = path[len(from.Path):]
+	}
+
+	c.Path = strings.TrimSuffix(to.Path, "/") + "/" + strings.TrimPrefix(path, "/")
+	c.RawPath = ""
+
+	return &c
+}
+
+func (o *callOptions) store(meta *Response) {
+	if o.response != nil {
+		*o.response = *meta
+	}
+}
+
+// responseKey holds the *Response of the API call being executed.
+type responseKey struct{}
+
+func (r *Response) fill(resp *http.Response, body []byte) {
+	r.StatusCode = resp.StatusCode
+	r.Header = resp.Header
+	r.Body = body
+	r.RequestId = resp.Header.Get(DefaultRequestIdHeader)
+	r.RateLimit = RateLimitHeaders{
+		Limit:     headerInt(resp.Header, "X-RateLimit-Limit"),
+		Remaining: headerInt(resp.Header, "X-RateLimit-Remaining"),
+		Reset:     headerInt(resp.Header, "X-RateLimit-Reset"),
+	}
+
+	if r.RequestId == "" && resp.Request != nil {
+		r.RequestId = resp.Request.Header.Get(DefaultRequestIdHeader)
+	}
+}
+
+func headerInt(header http.Header, key string) int {
+	v, _ := strconv.Atoi(header.Get(key))
+
+	return v
+}
@@ -0,0 +1,269 @@
+package smsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckErrorStrictSuccess(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMultipleChoices)
+		fmt.Fprint(w, `{"message":"multiple choices"}`)
+	})
+
+	_, err := client.Profile.Details(ctx)
+
+	if e, ok := err.(*ErrorResponse); !ok || e.Status != http.StatusMultipleChoices {
+		t.Errorf("Expected error for status 300, given: %v", err)
+	}
+}
+
+func TestCheckErrorHtmlGateway(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, `<html><body>502 Bad Gateway</body></html>`)
+	})
+
+	_, err := client.Profile.Details(ctx)
+
+	var unexpected *UnexpectedResponseError
+
+	if !errors.As(err, &unexpected) || unexpected.Status != http.StatusBadGateway || unexpected.ContentType != "text/html" {
+		t.Fatalf("Expected unexpected response error, given: %v", err)
+	}
+
+	if !errors.Is(err, ErrServer) || !IsRetryable(err) {
+		t.Errorf("Expected server error to be retryable")
+	}
+}
+
+func TestCheckErrorNonJsonSuccess(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html>captive portal</html>`)
+	})
+
+	_, err := client.Profile.Details(ctx)
+
+	if _, ok := err.(*UnexpectedResponseError); !ok {
+		t.Errorf("Expected unexpected response error, given: %v", err)
+	}
+}
+
+func TestCheckErrorEmptyBody(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := client.Profile.Details(ctx)
+
+	if _, ok := err.(*EmptyResponseError); !ok || !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected empty not found response error, given: %v", err)
+	}
+}
+
+func TestEmptySuccessResponseIsNotDecoded(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/contacts/1/groups/2", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := client.Contacts.BindContactToGroup(ctx, "1", "2"); err != nil {
+		t.Errorf("Expected no error, given: %v", err)
+	}
+}
+
+func TestRestErrorKey(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/subusers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, readFixture("error.json"))
+	})
+
+	_, err := client.Subusers.CreateUser(ctx, &User{})
+
+	expected := &ErrorResponse{
+		Status:  http.StatusBadRequest,
+		Key:     "invalid_request_data",
+		Message: "This value should not be blank.",
+	}
+
+	if e, ok := err.(*ErrorResponse); !ok || e.Status != expected.Status || e.Key != expected.Key || e.Message != expected.Message {
+		t.Errorf("Expected: %+v Given: %+v", expected, err)
+	}
+}
+
+func TestWithResponse(t *testing.T) {
+	client, mux, teardown := setupRetry(2)
+	defer teardown()
+
+	calls := 0
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		if r.Method == "GET" && calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("X-Request-Id", "abc")
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		fmt.Fprint(w, readFixture("sms/collection.json"))
+	})
+
+	var meta Response
+
+	c := ContextWithCallOptions(ctx, WithResponse(&meta))
+
+	if _, err := client.Sms.Get(c, "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if meta.StatusCode != 200 || meta.RequestId != "abc" || meta.Attempts != 2 || meta.Latency <= 0 {
+		t.Errorf("Unexpected response metadata: %+v", meta)
+	}
+
+	if meta.RateLimit.Limit != 100 || meta.RateLimit.Remaining != 99 {
+		t.Errorf("Unexpected rate limit: %+v", meta.RateLimit)
+	}
+
+	if string(meta.Body) != readFixture("sms/collection.json") {
+		t.Errorf("Unexpected body: %s", meta.Body)
+	}
+
+	if _, err := client.Sms.Send(c, "48100200300", "test", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if meta.Attempts != 1 {
+		t.Errorf("Expected metadata to be reset between calls, given: %+v", meta)
+	}
+}
+
+func TestCallOptionsHeaderAndAccessToken(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var given http.Header
+
+	mux.HandleFunc("/contacts/1", func(w http.ResponseWriter, r *http.Request) {
+		given = r.Header
+
+		fmt.Fprint(w, readFixture("contacts/contact.json"))
+	})
+
+	var meta Response
+
+	_, err := client.Contacts.UpdateContact(ctx, "1", &Contact{FirstName: "test"},
+		WithHeader("X-Idempotency-Key", "abc"),
+		WithAccessToken("subuser-token"),
+		WithResponse(&meta),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if given.Get("X-Idempotency-Key") != "abc" || given.Get("Authorization") != "Bearer subuser-token" {
+		t.Errorf("Unexpected headers: %v", given)
+	}
+
+	if meta.StatusCode != http.StatusOK {
+		t.Errorf("Expected captured response, given: %+v", meta)
+	}
+}
+
+func TestCallOptionsOverrideContextOptions(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var given []string
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		given = append(given, r.Header.Get("X-Test"))
+
+		fmt.Fprint(w, `{"username":"test"}`)
+	})
+
+	c := ContextWithCallOptions(ctx, WithHeader("X-Test", "context"))
+
+	client.Profile.Details(c)
+	client.Profile.Details(c, WithHeader("X-Test", "call"))
+	client.Profile.Details(c)
+
+	if strings.Join(given, ",") != "context,call,context" {
+		t.Errorf("Unexpected headers: %v", given)
+	}
+}
+
+func TestCallOptionsTimeout(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	_, err := client.Profile.Details(ctx, WithCallTimeout(10*time.Millisecond))
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, given: %v", err)
+	}
+}
+
+func TestCallOptionsBaseUrl(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	other, otherMux, otherTeardown := setup()
+	defer otherTeardown()
+
+	called := false
+
+	otherMux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+
+		fmt.Fprint(w, `{"username":"other"}`)
+	})
+
+	result, err := client.Profile.Details(ctx, WithCallBaseURL(other.BaseUrl.String()))
+
+	if err != nil || !called || result.Username != "other" {
+		t.Errorf("Expected call to other base url, given: %+v %v", result, err)
+	}
+
+	_, err = client.Profile.Details(ctx, WithCallBaseURL("not a url"))
+
+	if err != ErrInvalidBaseUrl {
+		t.Errorf("Expected invalid base url error, given: %v", err)
+	}
+}
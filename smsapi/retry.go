@@ -0,0 +1,226 @@
+package smsapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 500 * time.Millisecond
+	DefaultRetryMaxDelay    = 10 * time.Second
+	DefaultRetryJitter      = 0.5
+)
+
+// RetryPolicy describes when and how Client repeats failed requests.
+//
+// A request is retried when the transport fails (connection reset, timeout...),
+// when the HTTP status is listed in RetryableStatuses or when the API reports
+// one of RetryableCodes. Legacy message sends (/sms.do, /mms.do, /vms.do) are
+// never repeated unless they carry both `idx` and `check_idx`, which lets
+// SMSAPI reject the duplicate instead of sending the message twice. Requests
+// sending a message without such protection, like MFA codes, are never repeated.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. It doubles with every
+	// following attempt up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// Jitter is the fraction (0..1) of the computed delay that is randomized.
+	Jitter float64
+
+	RetryableStatuses []int
+	RetryableCodes    []int
+}
+
+// DefaultRetryPolicy retries gateway failures, 429 responses and the legacy
+// "internal error" / "too many requests" API codes.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: DefaultRetryMaxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    DefaultRetryMaxDelay,
+		Jitter:      DefaultRetryJitter,
+		RetryableStatuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableCodes: []int{
+			ErrorCodeInternal,
+			ErrorCodeTooManyConnections,
+			ErrorCodeTooManyRequests,
+			ErrorCodeInternalSystem,
+			ErrorCodeInternalSystemOther,
+		},
+	}
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	switch e := err.(type) {
+	case *ErrorResponse:
+		return containsInt(p.RetryableStatuses, e.Status) || containsInt(p.RetryableCodes, e.Code)
+	case *EmptyResponseError:
+		return containsInt(p.RetryableStatuses, e.Status)
+	case *UnexpectedResponseError:
+		return containsInt(p.RetryableStatuses, e.Status)
+	}
+
+	// Transport level failure, the request may not have reached the API.
+	return true
+}
+
+// backoff returns the delay before the given retry (attempt counts from 1).
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay = delay*(1-jitter) + rand.Float64()*delay*jitter
+	}
+
+	return time.Duration(delay)
+}
+
+// retryAfter parses the Retry-After header given either in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+
+		if delay < 0 {
+			delay = 0
+		}
+
+		return delay, true
+	}
+
+	return 0, false
+}
+
+var legacySendPaths = []string{"/sms.do", "/mms.do", "/vms.do"}
+
+// nonIdempotentSendPaths send a message on POST and have no way to detect a duplicate.
+var nonIdempotentSendPaths = []string{"/mfa/codes"}
+
+// isReplaySafe reports whether repeating the request cannot cause a duplicated message.
+func isReplaySafe(req *http.Request, body []byte) bool {
+	if req.Method != http.MethodPost {
+		return true
+	}
+
+	if hasPathSuffix(req.URL.Path, nonIdempotentSendPaths) {
+		return false
+	}
+
+	if !isLegacySendPath(req.URL.Path) {
+		return true
+	}
+
+	payload := map[string]interface{}{}
+
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&payload); err != nil {
+		return false
+	}
+
+	if _, ok := payload["sch_del"]; ok {
+		return true
+	}
+
+	return isSet(payload["idx"]) && isSet(payload["check_idx"])
+}
+
+func isLegacySendPath(path string) bool {
+	return hasPathSuffix(path, legacySendPaths)
+}
+
+func hasPathSuffix(path string, suffixes []string) bool {
+	for _, p := range suffixes {
+		if strings.HasSuffix(path, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isSet(v interface{}) bool {
+	switch value := v.(type) {
+	case bool:
+		return value
+	case float64:
+		return value != 0
+	case string:
+		return value != "" && value != "0" && value != "false"
+	}
+
+	return false
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,247 @@
+package smsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func setupRetry(maxAttempts int) (*Client, *http.ServeMux, func()) {
+	client, mux, teardown := setup()
+
+	client.RetryPolicy = DefaultRetryPolicy()
+	client.RetryPolicy.MaxAttempts = maxAttempts
+	client.RetryPolicy.BaseDelay = time.Millisecond
+	client.RetryPolicy.MaxDelay = time.Millisecond
+
+	return client, mux, teardown
+}
+
+func TestRetryServerErrorThenSuccess(t *testing.T) {
+	client, mux, teardown := setupRetry(3)
+	defer teardown()
+
+	calls := 0
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		fmt.Fprint(w, `{"username":"test"}`)
+	})
+
+	result, err := client.Profile.Details(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 3 || result.Username != "test" {
+		t.Errorf("Expected 3 calls and username, given: %d %+v", calls, result)
+	}
+}
+
+func TestRetryStopsAfterMaxAttempts(t *testing.T) {
+	client, mux, teardown := setupRetry(2)
+	defer teardown()
+
+	calls := 0
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, err := client.Profile.Details(ctx)
+
+	if e, ok := err.(*EmptyResponseError); !ok || e.Status != http.StatusBadGateway {
+		t.Errorf("Expected bad gateway error, given: %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, given: %d", calls)
+	}
+}
+
+func TestRetryNotRetryableStatus(t *testing.T) {
+	client, mux, teardown := setupRetry(3)
+	defer teardown()
+
+	calls := 0
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	client.Profile.Details(ctx)
+
+	if calls != 1 {
+		t.Errorf("Expected 1 call, given: %d", calls)
+	}
+}
+
+func TestRetryApiErrorCode(t *testing.T) {
+	client, mux, teardown := setupRetry(3)
+	defer teardown()
+
+	calls := 0
+
+	mux.HandleFunc("/hlr.do", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		if calls == 1 {
+			fmt.Fprint(w, `{"error":203,"message":"Too many requests"}`)
+			return
+		}
+
+		fmt.Fprint(w, readFixture("hlr/check_number.json"))
+	})
+
+	_, err := client.Hlr.CheckNumber(ctx, "48100200300")
+
+	if err != nil || calls != 2 {
+		t.Errorf("Expected success after 2 calls, given: %d %v", calls, err)
+	}
+}
+
+func TestRetryLegacySendWithoutIdx(t *testing.T) {
+	client, mux, teardown := setupRetry(3)
+	defer teardown()
+
+	calls := 0
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	client.Sms.Send(ctx, "48100200300", "test", "")
+
+	if calls != 1 {
+		t.Errorf("Expected 1 call, given: %d", calls)
+	}
+}
+
+func TestRetryMfaCodeNotRepeated(t *testing.T) {
+	client, mux, teardown := setupRetry(3)
+	defer teardown()
+
+	calls := 0
+
+	mux.HandleFunc("/mfa/codes", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	client.Mfa.CreateCode(ctx, &CreateMfaCode{PhoneNumber: "48100200300"})
+
+	if calls != 1 {
+		t.Errorf("Expected 1 call, given: %d", calls)
+	}
+}
+
+func TestRetryLegacySendWithIdxReplaysBody(t *testing.T) {
+	client, mux, teardown := setupRetry(3)
+	defer teardown()
+
+	var bodies []string
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		fmt.Fprint(w, readFixture("sms/collection.json"))
+	})
+
+	_, err := client.Sms.SendRaw(ctx, &Sms{To: "48100200300", Message: "test", Idx: 1, CheckIdx: true})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[0] == "" {
+		t.Errorf("Expected the same body twice, given: %q", bodies)
+	}
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	client, mux, teardown := setupRetry(2)
+	defer teardown()
+
+	calls := 0
+
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	c, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	started := time.Now()
+
+	_, err := client.Profile.Details(c)
+
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected too many requests error, given: %v", err)
+	}
+
+	if calls != 1 || time.Since(started) > 500*time.Millisecond {
+		t.Errorf("Expected to give up without waiting past the deadline, calls: %d", calls)
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+
+	if _, ok := retryAfter(resp); ok {
+		t.Error("Expected no Retry-After")
+	}
+
+	resp.Header.Set("Retry-After", "2")
+
+	if d, ok := retryAfter(resp); !ok || d != 2*time.Second {
+		t.Errorf("Expected 2s, given: %v", d)
+	}
+
+	resp.Header.Set("Retry-After", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+
+	if d, ok := retryAfter(resp); !ok || d != 0 {
+		t.Errorf("Expected 0 for past date, given: %v", d)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}
+
+	for i, e := range expected {
+		if given := policy.backoff(i + 1); given != e {
+			t.Errorf("Attempt %d expected: %v given: %v", i+1, e, given)
+		}
+	}
+
+	policy.Jitter = 0.5
+
+	for i := 0; i < 10; i++ {
+		if d := policy.backoff(1); d < 50*time.Millisecond || d > 100*time.Millisecond {
+			t.Errorf("Jittered delay out of range: %v", d)
+		}
+	}
+}
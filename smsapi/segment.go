@@ -0,0 +1,205 @@
+package smsapi
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+type SegmentEncoding string
+
+const (
+	SegmentEncodingGsm7 = SegmentEncoding("GSM-7")
+	SegmentEncodingUcs2 = SegmentEncoding("UCS-2")
+)
+
+// Part sizes in septets (GSM-7) or 16 bit code units (UCS-2). Multipart messages
+// carry a 6 byte UDH in every part, leaving less room for the content.
+const (
+	gsm7SinglePart = 160
+	gsm7MultiPart  = 153
+	ucs2SinglePart = 70
+	ucs2MultiPart  = 67
+)
+
+var (
+	ErrUnicodeNotAllowed = errors.New("smsapi: message contains characters outside of the GSM-7 alphabet")
+	ErrTooManyParts      = errors.New("smsapi: message exceeds the maximum number of parts")
+)
+
+// GSM 03.38 basic character set, without the escape character.
+const gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// GSM 03.38 extension table, each character is sent as escape and the character itself.
+const gsm7Extension = "\f^{}\\[~]|€"
+
+var gsm7Septets = func() map[rune]int {
+	result := map[rune]int{}
+
+	for _, r := range gsm7Basic {
+		result[r] = 1
+	}
+
+	for _, r := range gsm7Extension {
+		result[r] = 2
+	}
+
+	return result
+}()
+
+// SegmentOptions mirror the Sms parameters affecting the encoding and length of a message.
+type SegmentOptions struct {
+	// Normalize replaces characters as the normalize parameter does, see Normalize.
+	Normalize bool
+	// Nounicode makes messages requiring UCS-2 fail, as the API rejects them.
+	Nounicode bool
+	// MaxParts is the maximum allowed number of parts, 0 means no limit.
+	MaxParts int
+}
+
+type SegmentInfo struct {
+	// Message is the analysed message, normalized when requested.
+	Message  string
+	Encoding SegmentEncoding
+	// Characters is the number of characters of the message.
+	Characters int
+	// Units is the number of septets (GSM-7) or 16 bit code units (UCS-2).
+	Units int
+	Parts int
+	// PartSize is the number of units fitting in a single part.
+	PartSize int
+	// Remaining is the number of units left in the last part.
+	Remaining int
+	// UnicodeCharacters lists distinct characters forcing UCS-2, in order of appearance.
+	UnicodeCharacters []rune
+}
+
+// Segment analyses how the message is encoded and into how many parts it is split.
+// The info is returned along with ErrUnicodeNotAllowed or ErrTooManyParts when the message
+// would be rejected because of opts.
+func Segment(message string, opts *SegmentOptions) (*SegmentInfo, error) {
+	o := SegmentOptions{}
+
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Normalize {
+		message = Normalize(message)
+	}
+
+	info := &SegmentInfo{
+		Message:    message,
+		Encoding:   SegmentEncodingGsm7,
+		Characters: utf8.RuneCountInString(message),
+	}
+
+	seen := map[rune]bool{}
+
+	for _, r := range message {
+		if _, ok := gsm7Septets[r]; !ok && !seen[r] {
+			seen[r] = true
+			info.UnicodeCharacters = append(info.UnicodeCharacters, r)
+		}
+	}
+
+	var units []int
+
+	if len(info.UnicodeCharacters) == 0 {
+		units = charUnits(message, func(r rune) int { return gsm7Septets[r] })
+		info.Parts, info.PartSize, info.Remaining = countParts(units, gsm7SinglePart, gsm7MultiPart)
+	} else {
+		info.Encoding = SegmentEncodingUcs2
+		units = charUnits(message, ucs2Units)
+		info.Parts, info.PartSize, info.Remaining = countParts(units, ucs2SinglePart, ucs2MultiPart)
+	}
+
+	for _, u := range units {
+		info.Units += u
+	}
+
+	if o.Nounicode && info.Encoding == SegmentEncodingUcs2 {
+		return info, ErrUnicodeNotAllowed
+	}
+
+	if o.MaxParts > 0 && info.Parts > o.MaxParts {
+		return info, ErrTooManyParts
+	}
+
+	return info, nil
+}
+
+func charUnits(message string, units func(r rune) int) []int {
+	var result []int
+
+	for _, r := range message {
+		result = append(result, units(r))
+	}
+
+	return result
+}
+
+func ucs2Units(r rune) int {
+	if r > 0xFFFF {
+		return 2
+	}
+
+	return 1
+}
+
+// countParts packs characters into parts. Escape sequences and surrogate pairs
+// are never split between parts, so a part may hold one unit less than its size.
+func countParts(units []int, single, multi int) (parts, size, remaining int) {
+	total := 0
+
+	for _, u := range units {
+		total += u
+	}
+
+	if total == 0 {
+		return 1, single, single
+	}
+
+	if total <= single {
+		return 1, single, single - total
+	}
+
+	parts, used := 1, 0
+
+	for _, u := range units {
+		if used+u > multi {
+			parts++
+			used = 0
+		}
+
+		used += u
+	}
+
+	return parts, multi, multi - used
+}
+
+var normalizeReplacer = strings.NewReplacer(
+	"ą", "a", "ć", "c", "ę", "e", "ł", "l", "ń", "n", "ó", "o", "ś", "s", "ź", "z", "ż", "z",
+	"Ą", "A", "Ć", "C", "Ę", "E", "Ł", "L", "Ń", "N", "Ó", "O", "Ś", "S", "Ź", "Z", "Ż", "Z",
+	"č", "c", "ď", "d", "ě", "e", "ň", "n", "ř", "r", "š", "s", "ť", "t", "ů", "u", "ý", "y", "ž", "z",
+	"Č", "C", "Ď", "D", "Ě", "E", "Ň", "N", "Ř", "R", "Š", "S", "Ť", "T", "Ů", "U", "Ý", "Y", "Ž", "Z",
+	"á", "a", "í", "i", "ú", "u", "ô", "o", "ő", "o", "ű", "u", "ĺ", "l", "ľ", "l", "ŕ", "r",
+	"Á", "A", "Í", "I", "Ú", "U", "Ô", "O", "Ő", "O", "Ű", "U", "Ĺ", "L", "Ľ", "L", "Ŕ", "R",
+	"â", "a", "ê", "e", "î", "i", "û", "u", "ë", "e", "ï", "i", "ÿ", "y", "ç", "c", "œ", "oe",
+	"Â", "A", "Ê", "E", "Î", "I", "Û", "U", "Ë", "E", "Ï", "I", "Ÿ", "Y", "Œ", "OE",
+	"ă", "a", "ș", "s", "ş", "s", "ț", "t", "ţ", "t", "Ă", "A", "Ș", "S", "Ş", "S", "Ț", "T", "Ţ", "T",
+	"ā", "a", "ē", "e", "ī", "i", "ū", "u", "ģ", "g", "ķ", "k", "ļ", "l", "ņ", "n",
+	"Ā", "A", "Ē", "E", "Ī", "I", "Ū", "U", "Ģ", "G", "Ķ", "K", "Ļ", "L", "Ņ", "N",
+	"ė", "e", "į", "i", "ų", "u", "Ė", "E", "Į", "I", "Ų", "U", "õ", "o", "Õ", "O",
+	"ğ", "g", "ı", "i", "İ", "I", "Ğ", "G",
+	"“", "\"", "”", "\"", "„", "\"", "«", "\"", "»", "\"", "‘", "'", "’", "'", "‚", "'",
+	"–", "-", "—", "-", "…", "...", " ", " ", "\t", " ",
+)
+
+// Normalize transliterates characters outside of the GSM-7 alphabet, such as Polish
+// diacritics and typographic quotes, to their closest GSM-7 equivalents, as the
+// normalize parameter of Sms does. Characters without an equivalent are left unchanged.
+func Normalize(message string) string {
+	return normalizeReplacer.Replace(message)
+}
@@ -0,0 +1,74 @@
+package smsapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSegment(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   string
+		encoding  SegmentEncoding
+		units     int
+		parts     int
+		remaining int
+	}{
+		{"empty", "", SegmentEncodingGsm7, 0, 1, 160},
+		{"single gsm", "Hello", SegmentEncodingGsm7, 5, 1, 155},
+		{"full gsm", strings.Repeat("a", 160), SegmentEncodingGsm7, 160, 1, 0},
+		{"multipart gsm", strings.Repeat("a", 161), SegmentEncodingGsm7, 161, 2, 145},
+		{"extension", "€[]", SegmentEncodingGsm7, 6, 1, 154},
+		{"escape not split", strings.Repeat("a", 152) + "€" + strings.Repeat("a", 10), SegmentEncodingGsm7, 164, 2, 141},
+		{"single ucs2", "Zażółć", SegmentEncodingUcs2, 6, 1, 64},
+		{"multipart ucs2", strings.Repeat("ą", 71), SegmentEncodingUcs2, 71, 2, 63},
+		{"surrogate pair", "😀", SegmentEncodingUcs2, 2, 1, 68},
+	}
+
+	for _, test := range tests {
+		info, err := Segment(test.message, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if info.Encoding != test.encoding || info.Units != test.units || info.Parts != test.parts || info.Remaining != test.remaining {
+			t.Errorf("%s: unexpected info %+v", test.name, info)
+		}
+	}
+}
+
+func TestSegmentUnicodeCharacters(t *testing.T) {
+	info, _ := Segment("Zażółć gęślą", nil)
+
+	expected := []rune("żółćęśą")
+
+	if !reflect.DeepEqual(info.UnicodeCharacters, expected) {
+		t.Errorf("Expected: %q given: %q", string(expected), string(info.UnicodeCharacters))
+	}
+}
+
+func TestSegmentOptions(t *testing.T) {
+	info, err := Segment("Zażółć gęślą jaźń", &SegmentOptions{Normalize: true, Nounicode: true})
+
+	if err != nil || info.Encoding != SegmentEncodingGsm7 || info.Message != "Zazolc gesla jazn" {
+		t.Errorf("Expected normalized GSM-7 message, given: %+v %v", info, err)
+	}
+
+	if _, err := Segment("Zażółć", &SegmentOptions{Nounicode: true}); err != ErrUnicodeNotAllowed {
+		t.Errorf("Expected ErrUnicodeNotAllowed, given: %v", err)
+	}
+
+	if _, err := Segment(strings.Repeat("a", 161), &SegmentOptions{MaxParts: 1}); err != ErrTooManyParts {
+		t.Errorf("Expected ErrTooManyParts, given: %v", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	given := Normalize("„Łódź” – żółw…")
+
+	if given != "\"Lodz\" - zolw..." {
+		t.Errorf("Unexpected normalized message: %q", given)
+	}
+}
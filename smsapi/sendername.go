@@ -3,6 +3,7 @@ package smsapi
 import (
 	"context"
 	"fmt"
+	"iter"
 )
 
 type SenderApi struct {
@@ -25,40 +26,55 @@ type SenderCollectionResponse struct {
 	Collection []*SenderResponse `json:"collection"`
 }
 
-func (senderApi *SenderApi) Get(ctx context.Context, name string) (*SenderResponse, error) {
+func (senderApi *SenderApi) Get(ctx context.Context, name string, opts ...CallOption) (*SenderResponse, error) {
+	ctx = withOperation(ctx, "SenderApi.Get")
+
 	uri := fmt.Sprintf("/sms/sendernames/%s", name)
 
 	var result = new(SenderResponse)
 
-	err := senderApi.client.Get(ctx, uri, result)
+	err := senderApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
-func (senderApi *SenderApi) GetAll(ctx context.Context) (*SenderCollectionResponse, error) {
+func (senderApi *SenderApi) GetAll(ctx context.Context, opts ...CallOption) (*SenderCollectionResponse, error) {
+	ctx = withOperation(ctx, "SenderApi.GetAll")
+
 	var result = new(SenderCollectionResponse)
 
-	err := senderApi.client.Get(ctx, "/sms/sendernames", result)
+	err := senderApi.client.Get(ctx, "/sms/sendernames", result, opts...)
 
 	return result, err
 }
 
-func (senderApi *SenderApi) Create(ctx context.Context, name string) (*SenderResponse, error) {
+// All iterates over sender names.
+func (senderApi *SenderApi) All(ctx context.Context, opts ...CallOption) iter.Seq2[*SenderResponse, error] {
+	ctx = withOperation(ctx, "SenderApi.All")
+
+	return Paginate[SenderResponse](ctx, senderApi.client, "/sms/sendernames", nil, opts...)
+}
+
+func (senderApi *SenderApi) Create(ctx context.Context, name string, opts ...CallOption) (*SenderResponse, error) {
+	ctx = withOperation(ctx, "SenderApi.Create")
+
 	sender := &Sender{
 		Name: name,
 	}
 
 	var result = new(SenderResponse)
 
-	err := senderApi.client.Post(ctx, "/sms/sendernames", result, sender)
+	err := senderApi.client.Post(ctx, "/sms/sendernames", result, sender, opts...)
 
 	return result, err
 }
 
-func (senderApi *SenderApi) Delete(ctx context.Context, name string) error {
+func (senderApi *SenderApi) Delete(ctx context.Context, name string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "SenderApi.Delete")
+
 	uri := fmt.Sprintf("/sms/sendernames/%s", name)
 
-	err := senderApi.client.Delete(ctx, uri)
+	err := senderApi.client.Delete(ctx, uri, opts...)
 
 	return err
 }
@@ -69,18 +85,22 @@ type SendernameStatement struct {
 
 // GetStatement returns the current statement that must be agreed to when
 // requesting a new sendername.
-func (senderApi *SenderApi) GetStatement(ctx context.Context) (*SendernameStatement, error) {
+func (senderApi *SenderApi) GetStatement(ctx context.Context, opts ...CallOption) (*SendernameStatement, error) {
+	ctx = withOperation(ctx, "SenderApi.GetStatement")
+
 	var result = new(SendernameStatement)
 
-	err := senderApi.client.Get(ctx, "/sms/sendernames/statement", result)
+	err := senderApi.client.Get(ctx, "/sms/sendernames/statement", result, opts...)
 
 	return result, err
 }
 
-func (senderApi *SenderApi) MakeDefault(ctx context.Context, name string) error {
+func (senderApi *SenderApi) MakeDefault(ctx context.Context, name string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "SenderApi.MakeDefault")
+
 	uri := fmt.Sprintf("/sms/sendernames/%s/commands/make_default", name)
 
-	err := senderApi.client.Post(ctx, uri, nil, nil)
+	err := senderApi.client.Post(ctx, uri, nil, nil, opts...)
 
 	return err
 }
@@ -24,9 +24,11 @@ type ShipmentCountryVolumeFilters struct {
 }
 
 // ListCountryVolumes lists SMS shipment usage per country for a given year/month.
-func (api *ShipmentApi) ListCountryVolumes(ctx context.Context, filters *ShipmentCountryVolumeFilters) (*ShipmentCountryVolumeCollection, error) {
+func (api *ShipmentApi) ListCountryVolumes(ctx context.Context, filters *ShipmentCountryVolumeFilters, opts ...CallOption) (*ShipmentCountryVolumeCollection, error) {
+	ctx = withOperation(ctx, "ShipmentApi.ListCountryVolumes")
+
 	result := new(ShipmentCountryVolumeCollection)
 	uri, _ := addQueryParams("/shipment/country_volumes", filters)
-	err := api.client.Get(ctx, uri, result)
+	err := api.client.Get(ctx, uri, result, opts...)
 	return result, err
 }
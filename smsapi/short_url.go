@@ -3,6 +3,7 @@ package smsapi
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 )
 
@@ -32,26 +33,37 @@ type ClicksCollectionResponse struct {
 	Collection []*ClickResponse `json:"collection"`
 }
 
-func (shortUrlApi *ShortUrlApi) GetClicks(ctx context.Context, filters *ClicksCollectionFilters) (*ClicksCollectionResponse, error) {
+func (shortUrlApi *ShortUrlApi) GetClicks(ctx context.Context, filters *ClicksCollectionFilters, opts ...CallOption) (*ClicksCollectionResponse, error) {
+	ctx = withOperation(ctx, "ShortUrlApi.GetClicks")
+
 	uri, _ := addQueryParams("/short_url/clicks", filters)
 
 	var result = new(ClicksCollectionResponse)
 
-	err := shortUrlApi.client.Get(ctx, uri, result)
+	err := shortUrlApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
+// AllClicks iterates over short url clicks matching the filters.
+func (shortUrlApi *ShortUrlApi) AllClicks(ctx context.Context, filters *ClicksCollectionFilters, opts ...CallOption) iter.Seq2[*ClickResponse, error] {
+	ctx = withOperation(ctx, "ShortUrlApi.AllClicks")
+
+	return Paginate[ClickResponse](ctx, shortUrlApi.client, "/short_url/clicks", filters, opts...)
+}
+
 type ClicksReportResponse struct {
 	ReportUrl string `json:"link"`
 }
 
-func (shortUrlApi *ShortUrlApi) CreateReport(ctx context.Context, filters *ClicksCollectionFilters) (*ClicksReportResponse, error) {
+func (shortUrlApi *ShortUrlApi) CreateReport(ctx context.Context, filters *ClicksCollectionFilters, opts ...CallOption) (*ClicksReportResponse, error) {
+	ctx = withOperation(ctx, "ShortUrlApi.CreateReport")
+
 	var result = new(ClicksReportResponse)
 
 	uri, _ := addQueryParams("/short_url/clicks_reports", filters)
 
-	err := shortUrlApi.client.Post(ctx, uri, result, nil)
+	err := shortUrlApi.client.Post(ctx, uri, result, nil, opts...)
 
 	return result, err
 }
@@ -94,33 +106,48 @@ type LinksCollectionResponse struct {
 	Collection []*LinkResponse `json:"collection"`
 }
 
-func (shortUrlApi *ShortUrlApi) GetLinks(ctx context.Context) (*LinksCollectionResponse, error) {
+func (shortUrlApi *ShortUrlApi) GetLinks(ctx context.Context, opts ...CallOption) (*LinksCollectionResponse, error) {
+	ctx = withOperation(ctx, "ShortUrlApi.GetLinks")
+
 	var result = new(LinksCollectionResponse)
 
-	err := shortUrlApi.client.Get(ctx, "/short_url/links", result)
+	err := shortUrlApi.client.Get(ctx, "/short_url/links", result, opts...)
 
 	return result, err
 }
 
-func (shortUrlApi *ShortUrlApi) GetLink(ctx context.Context, id string) (*LinkResponse, error) {
+// AllLinks iterates over short url links.
+func (shortUrlApi *ShortUrlApi) AllLinks(ctx context.Context, opts ...CallOption) iter.Seq2[*LinkResponse, error] {
+	ctx = withOperation(ctx, "ShortUrlApi.AllLinks")
+
+	return Paginate[LinkResponse](ctx, shortUrlApi.client, "/short_url/links", nil, opts...)
+}
+
+func (shortUrlApi *ShortUrlApi) GetLink(ctx context.Context, id string, opts ...CallOption) (*LinkResponse, error) {
+	ctx = withOperation(ctx, "ShortUrlApi.GetLink")
+
 	var result = new(LinkResponse)
 
 	uri := fmt.Sprintf("/short_url/links/%s", id)
 
-	err := shortUrlApi.client.Get(ctx, uri, result)
+	err := shortUrlApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
-func (shortUrlApi *ShortUrlApi) CreateLinkRaw(ctx context.Context, link *Link) (*LinkResponse, error) {
+func (shortUrlApi *ShortUrlApi) CreateLinkRaw(ctx context.Context, link *Link, opts ...CallOption) (*LinkResponse, error) {
+	ctx = withOperation(ctx, "ShortUrlApi.CreateLinkRaw")
+
 	var result = new(LinkResponse)
 
-	err := shortUrlApi.client.Urlencoded(ctx, http.MethodPost, "/short_url/links", result, link)
+	err := shortUrlApi.client.Urlencoded(ctx, http.MethodPost, "/short_url/links", result, link, opts...)
 
 	return result, err
 }
 
-func (shortUrlApi *ShortUrlApi) CreateLink(ctx context.Context, targetUrl, name, description string) (*LinkResponse, error) {
+func (shortUrlApi *ShortUrlApi) CreateLink(ctx context.Context, targetUrl, name, description string, opts ...CallOption) (*LinkResponse, error) {
+	ctx = withOperation(ctx, "ShortUrlApi.CreateLink")
+
 	link := &Link{
 		Name:        name,
 		Description: description,
@@ -128,20 +155,24 @@ func (shortUrlApi *ShortUrlApi) CreateLink(ctx context.Context, targetUrl, name,
 		Type:        linkTypeUrl,
 	}
 
-	return shortUrlApi.CreateLinkRaw(ctx, link)
+	return shortUrlApi.CreateLinkRaw(ctx, link, opts...)
 }
 
-func (shortUrlApi *ShortUrlApi) UpdateLinkRaw(ctx context.Context, id string, link *Link) (*LinkResponse, error) {
+func (shortUrlApi *ShortUrlApi) UpdateLinkRaw(ctx context.Context, id string, link *Link, opts ...CallOption) (*LinkResponse, error) {
+	ctx = withOperation(ctx, "ShortUrlApi.UpdateLinkRaw")
+
 	uri := fmt.Sprintf("/short_url/links/%s", id)
 
 	var result = new(LinkResponse)
 
-	err := shortUrlApi.client.Urlencoded(ctx, http.MethodPut, uri, result, link)
+	err := shortUrlApi.client.Urlencoded(ctx, http.MethodPut, uri, result, link, opts...)
 
 	return result, err
 }
 
-func (shortUrlApi *ShortUrlApi) UpdateLink(ctx context.Context, id, targetUrl, name, description string) (*LinkResponse, error) {
+func (shortUrlApi *ShortUrlApi) UpdateLink(ctx context.Context, id, targetUrl, name, description string, opts ...CallOption) (*LinkResponse, error) {
+	ctx = withOperation(ctx, "ShortUrlApi.UpdateLink")
+
 	link := &Link{
 		Name:        name,
 		Description: description,
@@ -149,11 +180,13 @@ func (shortUrlApi *ShortUrlApi) UpdateLink(ctx context.Context, id, targetUrl, n
 		Type:        linkTypeUrl,
 	}
 
-	return shortUrlApi.UpdateLinkRaw(ctx, id, link)
+	return shortUrlApi.UpdateLinkRaw(ctx, id, link, opts...)
 }
 
-func (shortUrlApi *ShortUrlApi) DeleteLink(ctx context.Context, id string) error {
+func (shortUrlApi *ShortUrlApi) DeleteLink(ctx context.Context, id string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "ShortUrlApi.DeleteLink")
+
 	uri := fmt.Sprintf("/short_url/links/%s", id)
 
-	return shortUrlApi.client.Delete(ctx, uri)
+	return shortUrlApi.client.Delete(ctx, uri, opts...)
 }
@@ -41,35 +41,55 @@ type SmsResultCollection struct {
 	Count int `json:"count"`
 
 	Collection []*SmsResponse `json:"list"`
+
+	// InvalidNumbers lists recipients rejected while the message was sent to the others.
+	InvalidNumbers []*InvalidNumber `json:"invalid_numbers,omitempty"`
 }
 
 type SmsResponse struct {
-	Id              string     `json:"id,omitempty"`
-	Points          Points     `json:"points,omitempty"`
-	Number          string     `json:"number,omitempty"`
-	DateSent        *Timestamp `json:"date_sent,omitempty"`
-	SubmittedNumber string     `json:"submitted_number,omitempty"`
-	Status          string     `json:"status,omitempty"`
-	Idx             string     `json:"idx,omitempty"`
-	Error           string     `json:"error,omitempty"`
-	Message         string     `json:"message,omitempty"`
-	Length          int        `json:"length,omitempty"`
-	Parts           int        `json:"parts,omitempty"`
+	Id              string        `json:"id,omitempty"`
+	Points          Points        `json:"points,omitempty"`
+	Number          string        `json:"number,omitempty"`
+	DateSent        *Timestamp    `json:"date_sent,omitempty"`
+	SubmittedNumber string        `json:"submitted_number,omitempty"`
+	Status          MessageStatus `json:"status,omitempty"`
+	Idx             string        `json:"idx,omitempty"`
+	Error           string        `json:"error,omitempty"`
+	Message         string        `json:"message,omitempty"`
+	Length          int           `json:"length,omitempty"`
+	Parts           int           `json:"parts,omitempty"`
 }
 
 type SmsApi struct {
 	client *Client
+	prices priceCache
 }
 
-func (smsApi *SmsApi) SendRaw(ctx context.Context, sms *Sms) (*SmsResultCollection, error) {
+func (smsApi *SmsApi) SendRaw(ctx context.Context, sms *Sms, opts ...CallOption) (*SmsResultCollection, error) {
+	ctx = withOperation(ctx, "SmsApi.SendRaw")
+
 	var result = new(SmsResultCollection)
 
-	err := smsApi.client.LegacyPost(ctx, "/sms.do", result, sms)
+	to, err := smsApi.client.normalizePhoneNumbers(sms.To)
+
+	if err != nil {
+		return result, err
+	}
+
+	if to != sms.To {
+		normalized := *sms
+		normalized.To = to
+		sms = &normalized
+	}
+
+	err = smsApi.client.LegacyPost(ctx, "/sms.do", result, sms, opts...)
 
 	return result, err
 }
 
-func (smsApi *SmsApi) Schedule(ctx context.Context, to, message string, from string, sendAt *Timestamp) (*SmsResultCollection, error) {
+func (smsApi *SmsApi) Schedule(ctx context.Context, to, message string, from string, sendAt *Timestamp, opts ...CallOption) (*SmsResultCollection, error) {
+	ctx = withOperation(ctx, "SmsApi.Schedule")
+
 	sms := &Sms{
 		To:      to,
 		Message: message,
@@ -77,20 +97,24 @@ func (smsApi *SmsApi) Schedule(ctx context.Context, to, message string, from str
 		Date:    sendAt,
 	}
 
-	return smsApi.SendRaw(ctx, sms)
+	return smsApi.SendRaw(ctx, sms, opts...)
 }
 
-func (smsApi *SmsApi) Send(ctx context.Context, to, message string, from string) (*SmsResultCollection, error) {
+func (smsApi *SmsApi) Send(ctx context.Context, to, message string, from string, opts ...CallOption) (*SmsResultCollection, error) {
+	ctx = withOperation(ctx, "SmsApi.Send")
+
 	sms := &Sms{
 		To:      to,
 		Message: message,
 		From:    from,
 	}
 
-	return smsApi.SendRaw(ctx, sms)
+	return smsApi.SendRaw(ctx, sms, opts...)
 }
 
-func (smsApi *SmsApi) SendFlash(ctx context.Context, to, message string, from string) (*SmsResultCollection, error) {
+func (smsApi *SmsApi) SendFlash(ctx context.Context, to, message string, from string, opts ...CallOption) (*SmsResultCollection, error) {
+	ctx = withOperation(ctx, "SmsApi.SendFlash")
+
 	sms := &Sms{
 		To:      to,
 		Message: message,
@@ -98,17 +122,19 @@ func (smsApi *SmsApi) SendFlash(ctx context.Context, to, message string, from st
 		Flash:   true,
 	}
 
-	return smsApi.SendRaw(ctx, sms)
+	return smsApi.SendRaw(ctx, sms, opts...)
 }
 
-func (smsApi *SmsApi) SendToGroup(ctx context.Context, group, message string, from string) (*SmsResultCollection, error) {
+func (smsApi *SmsApi) SendToGroup(ctx context.Context, group, message string, from string, opts ...CallOption) (*SmsResultCollection, error) {
+	ctx = withOperation(ctx, "SmsApi.SendToGroup")
+
 	sms := &Sms{
 		Group:   group,
 		Message: message,
 		From:    from,
 	}
 
-	return smsApi.SendRaw(ctx, sms)
+	return smsApi.SendRaw(ctx, sms, opts...)
 }
 
 type SmsRemoveResult struct {
@@ -119,19 +145,23 @@ type SmsRemoveResult struct {
 	} `json:"list"`
 }
 
-func (smsApi *SmsApi) RemoveScheduled(ctx context.Context, id string) (*SmsRemoveResult, error) {
+func (smsApi *SmsApi) RemoveScheduled(ctx context.Context, id string, opts ...CallOption) (*SmsRemoveResult, error) {
+	ctx = withOperation(ctx, "SmsApi.RemoveScheduled")
+
 	var result = new(SmsRemoveResult)
 
 	payload := struct {
 		SchDel string `json:"sch_del"`
 	}{SchDel: id}
 
-	err := smsApi.client.LegacyPost(ctx, "/sms.do", result, payload)
+	err := smsApi.client.LegacyPost(ctx, "/sms.do", result, payload, opts...)
 
 	return result, err
 }
 
-func (smsApi *SmsApi) Get(ctx context.Context, id string) (*SmsResultCollection, error) {
+func (smsApi *SmsApi) Get(ctx context.Context, id string, opts ...CallOption) (*SmsResultCollection, error) {
+	ctx = withOperation(ctx, "SmsApi.Get")
+
 	var result = new(SmsResultCollection)
 
 	v := struct {
@@ -140,7 +170,7 @@ func (smsApi *SmsApi) Get(ctx context.Context, id string) (*SmsResultCollection,
 
 	uri, _ := addQueryParams("/sms.do", v)
 
-	err := smsApi.client.LegacyGet(ctx, uri, result)
+	err := smsApi.client.LegacyGet(ctx, uri, result, opts...)
 
 	return result, err
 }
@@ -0,0 +1,313 @@
+package smsapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultBulkBatchSize = 1000
+	DefaultBulkWorkers   = 4
+)
+
+var ErrMixedIdx = errors.New("smsapi: idx must be set for all recipients or none")
+
+var ErrMissingSms = errors.New("smsapi: message parameters are missing")
+
+// ErrBulkSmsIdx is returned when Sms.Idx or Sms.CheckIdx is set for a bulk send. A single
+// idx would be repeated in every batch and rejected as a duplicate, Recipient.Idx is sent
+// with check_idx instead.
+var ErrBulkSmsIdx = errors.New("smsapi: bulk sends take Recipient.Idx instead of Sms.Idx and CheckIdx")
+
+type Recipient struct {
+	PhoneNumber string
+	// Idx is a custom message id reported back in SmsResponse and delivery callbacks.
+	// It is used to resume interrupted bulk sends, see BulkOptions.SkipIdx.
+	Idx string
+}
+
+// BulkOptions configures SmsApi.SendBulk, nil means batches of DefaultBulkBatchSize
+// recipients sent by DefaultBulkWorkers workers.
+type BulkOptions struct {
+	// BatchSize is the number of recipients sent in a single request.
+	BatchSize int
+	// Workers is the number of batches sent concurrently.
+	Workers int
+	// SkipIdx lists Idx values already sent, e.g. by an interrupted run, see BulkReport.SentIdx.
+	SkipIdx []string
+}
+
+// BulkFailure is a batch rejected by the API as a whole.
+type BulkFailure struct {
+	Recipients []Recipient
+	Err        error
+}
+
+type BulkReport struct {
+	Sent    []*SmsResponse
+	Failed  []*BulkFailure
+	Invalid []*InvalidNumber
+	// Skipped counts recipients omitted because of BulkOptions.SkipIdx.
+	Skipped int
+	Points  Points
+}
+
+// SentIdx returns Idx values of sent messages, to be passed to BulkOptions.SkipIdx
+// when the send is repeated.
+func (r *BulkReport) SentIdx() []string {
+	var result []string
+
+	for _, sms := range r.Sent {
+		if sms.Idx != "" {
+			result = append(result, sms.Idx)
+		}
+	}
+
+	return result
+}
+
+// FailedRecipients returns recipients of all failed batches.
+func (r *BulkReport) FailedRecipients() []Recipient {
+	var result []Recipient
+
+	for _, failure := range r.Failed {
+		result = append(result, failure.Recipients...)
+	}
+
+	return result
+}
+
+// bulkSms overrides Sms.Idx with the "|" separated list of per-recipient values.
+type bulkSms struct {
+	*Sms
+	Idx string `json:"idx,omitempty"`
+}
+
+type bulkBatch struct {
+	sms        *Sms
+	recipients []Recipient
+}
+
+type bulkResult struct {
+	sent    []*SmsResponse
+	invalid []*InvalidNumber
+	failure *BulkFailure
+}
+
+// SendBulk sends the message to all recipients. Recipients are split into batches sent
+// concurrently, each batch is a single /sms.do request with comma separated numbers.
+// Per-recipient Idx values are sent as a "|" separated list together with check_idx,
+// so that retried or resumed batches are not delivered twice. Sms.Idx and Sms.CheckIdx are
+// rejected with ErrBulkSmsIdx.
+//
+// Failures of single batches are collected in the report, the error is returned only when
+// the context is done or the input is invalid.
+func (smsApi *SmsApi) SendBulk(ctx context.Context, recipients []Recipient, sms *Sms, options *BulkOptions, opts ...CallOption) (*BulkReport, error) {
+	ctx = withOperation(ctx, "SmsApi.SendBulk")
+
+	o := options.withDefaults()
+	report := new(BulkReport)
+
+	if sms == nil {
+		return report, ErrMissingSms
+	}
+
+	if sms.Idx != 0 || sms.CheckIdx {
+		return report, ErrBulkSmsIdx
+	}
+
+	recipients, report.Skipped = skipSent(recipients, o.SkipIdx)
+
+	if err := validateIdx(recipients); err != nil {
+		return report, err
+	}
+
+	var batches []*bulkBatch
+
+	for _, chunk := range chunkRecipients(recipients, o.BatchSize) {
+		batches = append(batches, &bulkBatch{sms: sms, recipients: chunk})
+	}
+
+	err := smsApi.sendBatches(ctx, batches, o.Workers, report, opts)
+
+	return report, err
+}
+
+func (smsApi *SmsApi) sendBatches(ctx context.Context, batches []*bulkBatch, workers int, report *BulkReport, opts []CallOption) error {
+	results := make([]*bulkResult, len(batches))
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := range jobs {
+				results[index] = smsApi.sendBatch(ctx, batches[index], opts)
+			}
+		}()
+	}
+
+	var err error
+
+dispatch:
+	for index := range batches {
+		select {
+		case jobs <- index:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break dispatch
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		report.Sent = append(report.Sent, result.sent...)
+		report.Invalid = append(report.Invalid, result.invalid...)
+
+		if result.failure != nil {
+			report.Failed = append(report.Failed, result.failure)
+		}
+
+		for _, sent := range result.sent {
+			report.Points += sent.Points
+		}
+	}
+
+	return err
+}
+
+func (smsApi *SmsApi) sendBatch(ctx context.Context, batch *bulkBatch, opts []CallOption) *bulkResult {
+	var numbers, idx []string
+	var valid []Recipient
+	var invalid []*InvalidNumber
+
+	for _, recipient := range batch.recipients {
+		number, err := smsApi.client.normalizePhoneNumber(recipient.PhoneNumber)
+
+		if err != nil {
+			invalid = append(invalid, &InvalidNumber{SubmittedNumber: recipient.PhoneNumber, Message: err.Error()})
+
+			continue
+		}
+
+		numbers = append(numbers, number)
+		idx = append(idx, recipient.Idx)
+		valid = append(valid, recipient)
+	}
+
+	if len(numbers) == 0 {
+		return &bulkResult{invalid: invalid}
+	}
+
+	sms := *batch.sms
+	sms.To = strings.Join(numbers, ",")
+	sms.Group = ""
+
+	payload := &bulkSms{Sms: &sms}
+
+	if idx[0] != "" {
+		payload.Idx = strings.Join(idx, "|")
+		sms.CheckIdx = true
+	}
+
+	result := new(SmsResultCollection)
+
+	err := smsApi.client.LegacyPost(ctx, "/sms.do", result, payload, opts...)
+
+	if err != nil {
+		failure := &bulkResult{failure: &BulkFailure{Recipients: valid, Err: err}, invalid: invalid}
+
+		var errorResponse *ErrorResponse
+
+		if errors.As(err, &errorResponse) {
+			failure.invalid = append(failure.invalid, errorResponse.InvalidNumbers...)
+		}
+
+		return failure
+	}
+
+	return &bulkResult{sent: result.Collection, invalid: append(invalid, result.InvalidNumbers...)}
+}
+
+func (o *BulkOptions) withDefaults() BulkOptions {
+	result := BulkOptions{}
+
+	if o != nil {
+		result = *o
+	}
+
+	if result.BatchSize < 1 {
+		result.BatchSize = DefaultBulkBatchSize
+	}
+
+	if result.Workers < 1 {
+		result.Workers = DefaultBulkWorkers
+	}
+
+	return result
+}
+
+func skipSent(recipients []Recipient, skipIdx []string) ([]Recipient, int) {
+	if len(skipIdx) == 0 {
+		return recipients, 0
+	}
+
+	skip := make(map[string]bool, len(skipIdx))
+
+	for _, idx := range skipIdx {
+		skip[idx] = true
+	}
+
+	result := make([]Recipient, 0, len(recipients))
+
+	for _, recipient := range recipients {
+		if recipient.Idx != "" && skip[recipient.Idx] {
+			continue
+		}
+
+		result = append(result, recipient)
+	}
+
+	return result, len(recipients) - len(result)
+}
+
+func validateIdx(recipients []Recipient) error {
+	withIdx := 0
+
+	for _, recipient := range recipients {
+		if recipient.Idx != "" {
+			withIdx++
+		}
+	}
+
+	if withIdx != 0 && withIdx != len(recipients) {
+		return ErrMixedIdx
+	}
+
+	return nil
+}
+
+func chunkRecipients(recipients []Recipient, size int) [][]Recipient {
+	var chunks [][]Recipient
+
+	for size < len(recipients) {
+		recipients, chunks = recipients[size:], append(chunks, recipients[0:size:size])
+	}
+
+	if len(recipients) > 0 {
+		chunks = append(chunks, recipients)
+	}
+
+	return chunks
+}
@@ -0,0 +1,246 @@
+package smsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type bulkRequest struct {
+	To       string `json:"to"`
+	Message  string `json:"message"`
+	Idx      string `json:"idx"`
+	CheckIdx bool   `json:"check_idx"`
+}
+
+func serveBulk(mux *http.ServeMux, handle func(req *bulkRequest) string) *[]*bulkRequest {
+	var mu sync.Mutex
+	var requests []*bulkRequest
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		req := new(bulkRequest)
+		json.NewDecoder(r.Body).Decode(req)
+
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+
+		fmt.Fprint(w, handle(req))
+	})
+
+	return &requests
+}
+
+func sentList(req *bulkRequest) string {
+	numbers := strings.Split(req.To, ",")
+	idx := strings.Split(req.Idx, "|")
+
+	var items []string
+
+	for i, number := range numbers {
+		items = append(items, fmt.Sprintf(`{"id":"%s","number":"%s","idx":"%s","points":0.5}`, number, number, idx[i]))
+	}
+
+	return fmt.Sprintf(`{"count":%d,"list":[%s]}`, len(items), strings.Join(items, ","))
+}
+
+func recipients(count int, withIdx bool) []Recipient {
+	var result []Recipient
+
+	for i := 0; i < count; i++ {
+		recipient := Recipient{PhoneNumber: fmt.Sprintf("48100200%03d", i)}
+
+		if withIdx {
+			recipient.Idx = fmt.Sprintf("order-%d", i)
+		}
+
+		result = append(result, recipient)
+	}
+
+	return result
+}
+
+func TestSendBulkBatchesAndAggregates(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	requests := serveBulk(mux, sentList)
+
+	report, err := client.Sms.SendBulk(ctx, recipients(5, true), &Sms{Message: "test"}, &BulkOptions{BatchSize: 2, Workers: 2})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*requests) != 3 || len(report.Sent) != 5 || report.Points != 2.5 {
+		t.Fatalf("Expected 5 messages in 3 requests, given: %d requests %+v", len(*requests), report)
+	}
+
+	var tos []string
+
+	for _, req := range *requests {
+		if !req.CheckIdx || len(strings.Split(req.Idx, "|")) != len(strings.Split(req.To, ",")) {
+			t.Errorf("Expected idx for every number with check_idx, given: %+v", req)
+		}
+
+		tos = append(tos, req.To)
+	}
+
+	sort.Strings(tos)
+
+	expected := []string{"48100200000,48100200001", "48100200002,48100200003", "48100200004"}
+
+	if !reflect.DeepEqual(tos, expected) {
+		t.Errorf("Expected batches: %v given: %v", expected, tos)
+	}
+
+	for i, sms := range report.Sent {
+		if sms.Idx != fmt.Sprintf("order-%d", i) {
+			t.Errorf("Expected results in recipients order, given: %s at %d", sms.Idx, i)
+		}
+	}
+}
+
+func TestSendBulkCollectsFailuresAndInvalidNumbers(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	serveBulk(mux, func(req *bulkRequest) string {
+		if strings.HasPrefix(req.To, "48100200000") {
+			return `{"error":13,"message":"No correct phone numbers","invalid_numbers":[{"number":"48100200000","submitted_number":"48100200000","message":"Invalid phone number"}]}`
+		}
+
+		return `{"count":1,"list":[{"id":"1","points":0.5}],"invalid_numbers":[{"number":"48100200003","message":"Invalid phone number"}]}`
+	})
+
+	report, err := client.Sms.SendBulk(ctx, recipients(4, false), &Sms{Message: "test"}, &BulkOptions{BatchSize: 2})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Failed) != 1 || !errors.Is(report.Failed[0].Err, ErrInvalidRecipient) {
+		t.Fatalf("Expected 1 failed batch, given: %+v", report.Failed)
+	}
+
+	if len(report.FailedRecipients()) != 2 || len(report.Invalid) != 2 || len(report.Sent) != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
+
+func TestSendBulkResume(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	requests := serveBulk(mux, sentList)
+
+	all := recipients(4, true)
+
+	first, _ := client.Sms.SendBulk(ctx, all[:2], &Sms{Message: "test"}, nil)
+
+	report, err := client.Sms.SendBulk(ctx, all, &Sms{Message: "test"}, &BulkOptions{SkipIdx: first.SentIdx()})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Skipped != 2 || (*requests)[1].To != "48100200002,48100200003" {
+		t.Errorf("Expected already sent recipients to be skipped, given: %d %s", report.Skipped, (*requests)[1].To)
+	}
+}
+
+func TestSendBulkMixedIdx(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	mixed := append(recipients(1, true), recipients(1, false)...)
+
+	_, err := client.Sms.SendBulk(ctx, mixed, &Sms{Message: "test"}, nil)
+
+	if err != ErrMixedIdx {
+		t.Errorf("Expected ErrMixedIdx, given: %v", err)
+	}
+}
+
+func TestChunkRecipients(t *testing.T) {
+	tests := []struct {
+		count    int
+		size     int
+		expected []int
+	}{
+		{0, 2, nil},
+		{1, 2, []int{1}},
+		{4, 2, []int{2, 2}},
+		{5, 2, []int{2, 2, 1}},
+	}
+
+	for _, test := range tests {
+		var given []int
+
+		for _, chunk := range chunkRecipients(recipients(test.count, false), test.size) {
+			given = append(given, len(chunk))
+		}
+
+		if !reflect.DeepEqual(given, test.expected) {
+			t.Errorf("%d by %d expected: %v given: %v", test.count, test.size, test.expected, given)
+		}
+	}
+}
+
+func TestSendBulkMissingSms(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	_, err := client.Sms.SendBulk(ctx, recipients(2, false), nil, nil)
+
+	if !errors.Is(err, ErrMissingSms) {
+		t.Errorf("Expected ErrMissingSms, given: %v", err)
+	}
+}
+
+func TestSendBulkSmsIdx(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	requests := serveBulk(mux, sentList)
+
+	for _, sms := range []*Sms{{Message: "test", Idx: 7, CheckIdx: true}, {Message: "test", CheckIdx: true}} {
+		_, err := client.Sms.SendBulk(ctx, recipients(5, false), sms, &BulkOptions{BatchSize: 2})
+
+		if !errors.Is(err, ErrBulkSmsIdx) {
+			t.Errorf("Expected ErrBulkSmsIdx, given: %v", err)
+		}
+	}
+
+	if len(*requests) != 0 {
+		t.Errorf("Expected no requests, given: %d", len(*requests))
+	}
+
+	report, err := client.Sms.SendBulk(ctx, recipients(5, true), &Sms{Message: "test"}, &BulkOptions{BatchSize: 2})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+
+	for _, req := range *requests {
+		for _, idx := range strings.Split(req.Idx, "|") {
+			if seen[idx] {
+				t.Errorf("Expected idx to be sent once, given %s again", idx)
+			}
+
+			seen[idx] = true
+		}
+	}
+
+	if len(*requests) != 3 || len(seen) != 5 || len(report.Failed) != 0 {
+		t.Errorf("Expected distinct idx in 3 batches, given: %d requests %v", len(*requests), seen)
+	}
+}
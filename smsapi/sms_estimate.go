@@ -0,0 +1,293 @@
+package smsapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smsapi/smsapi-go/smsapi/phonenumber"
+)
+
+const (
+	DefaultPricingType   = "sms"
+	DefaultPriceCacheTTL = time.Hour
+)
+
+var (
+	ErrEstimateGroup  = errors.New("smsapi: cost of sending to a group cannot be estimated")
+	ErrUnknownCountry = errors.New("smsapi: country of the phone number is unknown")
+	ErrNoPrice        = errors.New("smsapi: no price for the country")
+	ErrMixedCurrency  = errors.New("smsapi: prices of recipients are in different currencies")
+)
+
+// EstimateOptions configures SmsApi.Estimate, nil means DefaultPricingType prices cached
+// for DefaultPriceCacheTTL, without a dry run.
+type EstimateOptions struct {
+	// PricingType is passed to ProfileApi.Prices.
+	PricingType string
+	// PriceCacheTTL is the maximum age of cached prices.
+	PriceCacheTTL time.Duration
+	// DryRun sends the message with Test set, nothing is delivered but the API
+	// validates it and reports points charged per recipient.
+	DryRun bool
+}
+
+type EstimateItem struct {
+	PhoneNumber string
+	// Country is the ISO 3166-1 alpha-2 code detected from the number prefix.
+	Country   string
+	Parts     int
+	UnitPrice *Money
+	Cost      *Money
+	// Server is the response of the dry run for this number.
+	Server *SmsResponse
+	// Err is set when the cost of this number cannot be estimated.
+	Err error
+}
+
+type SmsEstimate struct {
+	Segment *SegmentInfo
+	Items   []*EstimateItem
+	// Total is the sum of costs of items without errors, nil when their currencies differ.
+	Total *Money
+	// ServerPoints is the sum of points reported by the dry run.
+	ServerPoints Points
+	// Server is the dry run response.
+	Server *SmsResultCollection
+}
+
+// Estimate calculates the cost of sending the sms without sending it. Recipients must be
+// given in international format, or national format of PhoneNumberPolicy.DefaultRegion,
+// their countries are detected from country codes and
+// priced by ProfileApi.Prices. Prices differ between networks, as the network is not known
+// before sending, the highest price in the country is used.
+//
+// Prices are cached per pricing type, access token and base url. ErrMixedCurrency is returned
+// along with the items when their prices are in different currencies.
+func (smsApi *SmsApi) Estimate(ctx context.Context, sms *Sms, options *EstimateOptions, opts ...CallOption) (*SmsEstimate, error) {
+	ctx = withOperation(ctx, "SmsApi.Estimate")
+
+	o := options.withDefaults()
+
+	if sms == nil {
+		return nil, ErrMissingSms
+	}
+
+	if sms.Group != "" {
+		return nil, ErrEstimateGroup
+	}
+
+	segment, err := Segment(sms.Message, &SegmentOptions{Normalize: sms.Normalize, Nounicode: sms.Nounicode, MaxParts: sms.MaxParts})
+
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := smsApi.prices.get(ctx, smsApi.client, o.PricingType, o.PriceCacheTTL, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SmsEstimate{Segment: segment}
+	total := new(big.Rat)
+	currency, scale := "", 0
+
+	var mixed error
+
+	for _, number := range strings.Split(sms.To, ",") {
+		number = strings.TrimSpace(number)
+
+		if number == "" {
+			continue
+		}
+
+		item := &EstimateItem{PhoneNumber: number, Parts: segment.Parts}
+		result.Items = append(result.Items, item)
+
+		parsed, err := phonenumber.Parse(number, smsApi.client.defaultRegion())
+		item.Country = parsed.Region()
+
+		price, ok := prices[item.Country]
+
+		switch {
+		case err != nil:
+			item.Err = fmt.Errorf("%w: %w", ErrUnknownCountry, err)
+		case !ok:
+			item.Err = ErrNoPrice
+		default:
+			cost := new(big.Rat).Mul(price.value, big.NewRat(int64(item.Parts), 1))
+
+			item.UnitPrice = price.money
+			item.Cost = &Money{Value: cost.FloatString(price.scale), Currency: price.money.Currency}
+
+			if currency != "" && currency != price.money.Currency && mixed == nil {
+				mixed = fmt.Errorf("%w: %s and %s", ErrMixedCurrency, currency, price.money.Currency)
+			}
+
+			total.Add(total, cost)
+			currency, scale = price.money.Currency, max(scale, price.scale)
+		}
+	}
+
+	if mixed != nil {
+		return result, mixed
+	}
+
+	result.Total = &Money{Value: total.FloatString(scale), Currency: currency}
+
+	if o.DryRun {
+		err = smsApi.dryRun(ctx, sms, result, opts)
+	}
+
+	return result, err
+}
+
+func (smsApi *SmsApi) dryRun(ctx context.Context, sms *Sms, estimate *SmsEstimate, opts []CallOption) error {
+	test := *sms
+	test.Test = true
+
+	response, err := smsApi.SendRaw(ctx, &test, opts...)
+
+	if err != nil {
+		return err
+	}
+
+	estimate.Server = response
+
+	region := smsApi.client.defaultRegion()
+	byNumber := map[string]*EstimateItem{}
+
+	for _, item := range estimate.Items {
+		byNumber[smsapiFormat(item.PhoneNumber, region)] = item
+	}
+
+	for _, sent := range response.Collection {
+		estimate.ServerPoints += sent.Points
+
+		item, ok := byNumber[smsapiFormat(sent.SubmittedNumber, region)]
+
+		if !ok {
+			item, ok = byNumber[smsapiFormat(sent.Number, region)]
+		}
+
+		if ok {
+			item.Server = sent
+		}
+	}
+
+	return nil
+}
+
+func smsapiFormat(number, region string) string {
+	if parsed, err := phonenumber.Parse(number, region); err == nil {
+		return parsed.String()
+	}
+
+	return number
+}
+
+func (o *EstimateOptions) withDefaults() EstimateOptions {
+	result := EstimateOptions{}
+
+	if o != nil {
+		result = *o
+	}
+
+	if result.PricingType == "" {
+		result.PricingType = DefaultPricingType
+	}
+
+	if result.PriceCacheTTL <= 0 {
+		result.PriceCacheTTL = DefaultPriceCacheTTL
+	}
+
+	return result
+}
+
+type countryPrice struct {
+	money *Money
+	value *big.Rat
+	// scale is the number of decimal places of the price.
+	scale int
+}
+
+type cachedPrices struct {
+	byCountry map[string]*countryPrice
+	fetchedAt time.Time
+}
+
+// priceCache keeps the highest price per country for each pricing type and account, as
+// subusers (WithAccessToken) and other regions (WithCallBaseURL) have their own prices.
+type priceCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedPrices
+}
+
+func priceCacheKey(ctx context.Context, pricingType string, opts []CallOption) string {
+	o := callOptionsFromContext(ctx).with(opts)
+	token := sha256.Sum256([]byte(o.accessToken))
+
+	return pricingType + "|" + hex.EncodeToString(token[:]) + "|" + o.baseUrl
+}
+
+func (c *priceCache) get(ctx context.Context, client *Client, pricingType string, ttl time.Duration, opts []CallOption) (map[string]*countryPrice, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := priceCacheKey(ctx, pricingType, opts)
+
+	if entry, ok := c.entries[key]; ok && time.Since(entry.fetchedAt) < ttl {
+		return entry.byCountry, nil
+	}
+
+	response, err := client.Profile.Prices(ctx, pricingType, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	byCountry := map[string]*countryPrice{}
+
+	for _, price := range response.Collection {
+		if price.Price == nil {
+			continue
+		}
+
+		value, ok := new(big.Rat).SetString(price.Price.Value)
+
+		if !ok {
+			continue
+		}
+
+		country := strings.ToUpper(price.Country)
+
+		if current, ok := byCountry[country]; ok && current.value.Cmp(value) >= 0 {
+			continue
+		}
+
+		byCountry[country] = &countryPrice{money: price.Price, value: value, scale: decimalPlaces(price.Price.Value)}
+	}
+
+	if c.entries == nil {
+		c.entries = map[string]*cachedPrices{}
+	}
+
+	c.entries[key] = &cachedPrices{byCountry: byCountry, fetchedAt: time.Now()}
+
+	return byCountry, nil
+}
+
+func decimalPlaces(value string) int {
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		return len(value) - i - 1
+	}
+
+	return 0
+}
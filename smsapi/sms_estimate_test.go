@@ -0,0 +1,176 @@
+package smsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func servePrices(t *testing.T, mux *http.ServeMux) *int {
+	calls := 0
+
+	mux.HandleFunc("/profile/prices", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		assertRequestQueryParam(t, r, "type", "sms")
+		fmt.Fprint(w, `{"size":3,"collection":[
+			{"price":{"value":"0.16","currency":"PLN"},"country":"PL","network":"Plus"},
+			{"price":{"value":"0.17","currency":"PLN"},"country":"PL","network":"Orange"},
+			{"price":{"value":"0.35","currency":"PLN"},"country":"DE","network":"Vodafone"}
+		]}`)
+	})
+
+	return &calls
+}
+
+func TestEstimate(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := servePrices(t, mux)
+
+	sms := &Sms{To: "48500600700, +49 151 2345678,+1 202 555 0100,999", Message: strings.Repeat("a", 200)}
+
+	estimate, err := client.Sms.Estimate(ctx, sms, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if estimate.Segment.Parts != 2 || len(estimate.Items) != 4 {
+		t.Fatalf("Unexpected estimate: %+v", estimate)
+	}
+
+	pl, de, us, unknown := estimate.Items[0], estimate.Items[1], estimate.Items[2], estimate.Items[3]
+
+	if pl.Country != "PL" || pl.UnitPrice.Value != "0.17" || pl.Cost.Value != "0.34" {
+		t.Errorf("Expected the highest PL price, given: %+v", pl)
+	}
+
+	if de.Country != "DE" || de.Cost.Value != "0.70" {
+		t.Errorf("Unexpected DE item: %+v", de)
+	}
+
+	if !errors.Is(us.Err, ErrNoPrice) || !errors.Is(unknown.Err, ErrUnknownCountry) {
+		t.Errorf("Expected item errors, given: %v %v", us.Err, unknown.Err)
+	}
+
+	if *estimate.Total != (Money{Value: "1.04", Currency: "PLN"}) {
+		t.Errorf("Unexpected total: %+v", estimate.Total)
+	}
+
+	client.Sms.Estimate(ctx, sms, nil)
+
+	if *calls != 1 {
+		t.Errorf("Expected prices to be cached, given %d calls", *calls)
+	}
+}
+
+func TestEstimatePricesCachedPerAccount(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	calls := servePrices(t, mux)
+
+	sms := &Sms{To: "48500600700", Message: "test"}
+
+	client.Sms.Estimate(ctx, sms, nil)
+	client.Sms.Estimate(ctx, sms, nil, WithAccessToken("subuser"))
+	client.Sms.Estimate(ctx, sms, nil, WithAccessToken("subuser"))
+
+	if *calls != 2 {
+		t.Errorf("Expected prices fetched per access token, given %d calls", *calls)
+	}
+}
+
+func TestEstimateMixedCurrency(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/profile/prices", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"size":2,"collection":[
+			{"price":{"value":"0.16","currency":"PLN"},"country":"PL"},
+			{"price":{"value":"0.08","currency":"EUR"},"country":"DE"}
+		]}`)
+	})
+
+	estimate, err := client.Sms.Estimate(ctx, &Sms{To: "48500600700,+49 151 2345678", Message: "test"}, nil)
+
+	if !errors.Is(err, ErrMixedCurrency) {
+		t.Fatalf("Expected ErrMixedCurrency, given: %v", err)
+	}
+
+	if estimate.Total != nil || len(estimate.Items) != 2 || estimate.Items[1].Cost.Currency != "EUR" {
+		t.Errorf("Unexpected estimate: %+v", estimate)
+	}
+}
+
+func TestEstimateNationalNumbers(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	client.PhoneNumberPolicy = &PhoneNumberPolicy{DefaultRegion: "PL"}
+
+	mux.HandleFunc("/profile/prices", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"size":1,"collection":[{"price":{"value":"0.16","currency":"PLN"},"country":"PL"}]}`)
+	})
+
+	estimate, err := client.Sms.Estimate(ctx, &Sms{To: "500 600 700", Message: "test"}, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if estimate.Items[0].Country != "PL" || estimate.Items[0].Err != nil || estimate.Total.Value != "0.16" {
+		t.Errorf("Unexpected estimate: %+v %+v", estimate.Items[0], estimate.Total)
+	}
+}
+
+func TestEstimateMissingSms(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	if _, err := client.Sms.Estimate(ctx, nil, nil); !errors.Is(err, ErrMissingSms) {
+		t.Errorf("Expected ErrMissingSms, given: %v", err)
+	}
+}
+
+func TestEstimateDryRun(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	servePrices(t, mux)
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		req := new(Sms)
+		json.NewDecoder(r.Body).Decode(req)
+
+		if !req.Test {
+			t.Error("Expected test send")
+		}
+
+		fmt.Fprint(w, `{"count":1,"list":[{"id":"1","points":0.17,"number":"48500600700","submitted_number":"+48500600700"}]}`)
+	})
+
+	estimate, err := client.Sms.Estimate(ctx, &Sms{To: "+48500600700", Message: "test"}, &EstimateOptions{DryRun: true})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if estimate.ServerPoints != 0.17 || estimate.Items[0].Server == nil {
+		t.Errorf("Expected dry run results, given: %+v", estimate)
+	}
+}
+
+func TestEstimateRejectsGroup(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	if _, err := client.Sms.Estimate(ctx, &Sms{Group: "all"}, nil); err != ErrEstimateGroup {
+		t.Errorf("Expected ErrEstimateGroup, given: %v", err)
+	}
+}
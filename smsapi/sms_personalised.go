@@ -0,0 +1,217 @@
+package smsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+var ErrPersonalisedIdx = errors.New("smsapi: personalised recipients need a unique idx")
+
+var ErrMissingRenderer = errors.New("smsapi: message renderer is missing")
+
+// MessageRenderer renders the message of a single recipient from its parameters.
+type MessageRenderer interface {
+	Render(params map[string]string) (string, error)
+}
+
+type goTemplate struct {
+	t *template.Template
+}
+
+// NewGoTemplate parses a text/template message, e.g. "Hi {{.name}}, your order {{.id}} shipped".
+// Parameters missing for a recipient are reported as errors.
+func NewGoTemplate(text string) (MessageRenderer, error) {
+	t, err := template.New("message").Option("missingkey=error").Parse(text)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &goTemplate{t}, nil
+}
+
+func (g *goTemplate) Render(params map[string]string) (string, error) {
+	var b strings.Builder
+
+	err := g.t.Execute(&b, params)
+
+	return b.String(), err
+}
+
+var placeholderPattern = regexp.MustCompile(`\[%([^%\]]+)%\]`)
+
+type placeholderTemplate string
+
+// NewPlaceholderTemplate returns a renderer replacing SMSAPI style placeholders,
+// e.g. "[%1%]" or "[%name%]", with recipient parameters of the same name.
+func NewPlaceholderTemplate(text string) MessageRenderer {
+	return placeholderTemplate(text)
+}
+
+func (p placeholderTemplate) Render(params map[string]string) (string, error) {
+	var missing []string
+
+	result := placeholderPattern.ReplaceAllStringFunc(string(p), func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+
+		value, ok := params[name]
+
+		if !ok {
+			missing = append(missing, name)
+		}
+
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("smsapi: missing message parameters: %s", strings.Join(missing, ", "))
+	}
+
+	return result, nil
+}
+
+type PersonalisedRecipient struct {
+	PhoneNumber string
+	// Idx identifies the recipient in PersonalisedReport.Results, it is required and must be unique.
+	Idx    string
+	Params map[string]string
+}
+
+type PersonalisedResult struct {
+	Recipient *PersonalisedRecipient
+	// Message is the rendered message.
+	Message string
+	// Sms is set when the message was accepted by the API.
+	Sms *SmsResponse
+	// Err is set when rendering failed, the batch was rejected or the number was invalid.
+	Err error
+}
+
+type PersonalisedReport struct {
+	*BulkReport
+	// Results maps recipient Idx to its outcome, recipients skipped by BulkOptions.SkipIdx
+	// are not included.
+	Results map[string]*PersonalisedResult
+}
+
+// SendPersonalised renders the message separately for every recipient and sends it.
+// Recipients sharing the same rendered message are sent together in batches,
+// as in SendBulk. The sms provides the remaining parameters, e.g. From; its Message is replaced.
+//
+// Rendering errors are reported per recipient and do not stop other sends.
+func (smsApi *SmsApi) SendPersonalised(ctx context.Context, recipients []PersonalisedRecipient, renderer MessageRenderer, sms *Sms, options *BulkOptions, opts ...CallOption) (*PersonalisedReport, error) {
+	ctx = withOperation(ctx, "SmsApi.SendPersonalised")
+
+	o := options.withDefaults()
+	report := &PersonalisedReport{BulkReport: new(BulkReport), Results: map[string]*PersonalisedResult{}}
+
+	if sms == nil {
+		return report, ErrMissingSms
+	}
+
+	if renderer == nil {
+		return report, ErrMissingRenderer
+	}
+
+	byIdx := make(map[string]*PersonalisedRecipient, len(recipients))
+	plain := make([]Recipient, len(recipients))
+
+	for i := range recipients {
+		recipient := &recipients[i]
+
+		if recipient.Idx == "" || byIdx[recipient.Idx] != nil {
+			return report, ErrPersonalisedIdx
+		}
+
+		byIdx[recipient.Idx] = recipient
+		plain[i] = Recipient{PhoneNumber: recipient.PhoneNumber, Idx: recipient.Idx}
+	}
+
+	plain, report.Skipped = skipSent(plain, o.SkipIdx)
+
+	var bodies []string
+	groups := map[string][]Recipient{}
+
+	for _, r := range plain {
+		recipient := byIdx[r.Idx]
+		message, err := renderer.Render(recipient.Params)
+
+		report.Results[r.Idx] = &PersonalisedResult{Recipient: recipient, Message: message, Err: err}
+
+		if err != nil {
+			continue
+		}
+
+		if _, ok := groups[message]; !ok {
+			bodies = append(bodies, message)
+		}
+
+		groups[message] = append(groups[message], r)
+	}
+
+	var batches []*bulkBatch
+
+	for _, body := range bodies {
+		message := *sms
+		message.Message = body
+
+		for _, chunk := range chunkRecipients(groups[body], o.BatchSize) {
+			batches = append(batches, &bulkBatch{sms: &message, recipients: chunk})
+		}
+	}
+
+	err := smsApi.sendBatches(ctx, batches, o.Workers, report.BulkReport, opts)
+
+	report.mapResults(smsApi.client.defaultRegion())
+
+	return report, err
+}
+
+// mapResults assigns sent messages and failures of the bulk report to recipients.
+// Responses without idx are matched by phone number in the SMSAPI format, as numbers
+// are sent normalised by PhoneNumberPolicy.
+func (r *PersonalisedReport) mapResults(region string) {
+	byNumber := map[string]*PersonalisedResult{}
+
+	for _, result := range r.Results {
+		byNumber[smsapiFormat(result.Recipient.PhoneNumber, region)] = result
+	}
+
+	for _, sms := range r.Sent {
+		result, ok := r.Results[sms.Idx]
+
+		if !ok {
+			result, ok = byNumber[smsapiFormat(sms.SubmittedNumber, region)]
+		}
+
+		if !ok {
+			result, ok = byNumber[smsapiFormat(sms.Number, region)]
+		}
+
+		if ok {
+			result.Sms = sms
+		}
+	}
+
+	for _, failure := range r.Failed {
+		for _, recipient := range failure.Recipients {
+			r.Results[recipient.Idx].Err = failure.Err
+		}
+	}
+
+	for _, invalid := range r.Invalid {
+		result, ok := byNumber[smsapiFormat(invalid.SubmittedNumber, region)]
+
+		if !ok {
+			result, ok = byNumber[smsapiFormat(invalid.Number, region)]
+		}
+
+		if ok && result.Err == nil {
+			result.Err = fmt.Errorf("%w: %s", ErrInvalidRecipient, invalid.Message)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package smsapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPlaceholderTemplate(t *testing.T) {
+	renderer := NewPlaceholderTemplate("Hi [%name%], order [%1%] shipped")
+
+	given, err := renderer.Render(map[string]string{"name": "Anna", "1": "A-1"})
+
+	if err != nil || given != "Hi Anna, order A-1 shipped" {
+		t.Errorf("Unexpected message: %q %v", given, err)
+	}
+
+	_, err = renderer.Render(map[string]string{"name": "Anna"})
+
+	if err == nil || !strings.Contains(err.Error(), "1") {
+		t.Errorf("Expected missing parameter error, given: %v", err)
+	}
+}
+
+func TestGoTemplate(t *testing.T) {
+	renderer, err := NewGoTemplate("Hi {{.name}}")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	given, err := renderer.Render(map[string]string{"name": "Anna"})
+
+	if err != nil || given != "Hi Anna" {
+		t.Errorf("Unexpected message: %q %v", given, err)
+	}
+
+	if _, err := renderer.Render(map[string]string{}); err == nil {
+		t.Error("Expected missing parameter error")
+	}
+}
+
+func TestSendPersonalisedGroupsIdenticalMessages(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	requests := serveBulk(mux, func(req *bulkRequest) string {
+		if req.To == "48100200004" {
+			return `{"error":13,"message":"No correct phone numbers"}`
+		}
+
+		return sentList(req)
+	})
+
+	renderer := NewPlaceholderTemplate("Your order [%id%] shipped")
+
+	recipients := []PersonalisedRecipient{
+		{PhoneNumber: "48100200001", Idx: "a", Params: map[string]string{"id": "1"}},
+		{PhoneNumber: "48100200002", Idx: "b", Params: map[string]string{"id": "2"}},
+		{PhoneNumber: "48100200003", Idx: "c", Params: map[string]string{"id": "1"}},
+		{PhoneNumber: "48100200004", Idx: "d", Params: map[string]string{"id": "3"}},
+		{PhoneNumber: "48100200005", Idx: "e", Params: map[string]string{}},
+	}
+
+	report, err := client.Sms.SendPersonalised(ctx, recipients, renderer, &Sms{From: "Test"}, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*requests) != 3 {
+		t.Fatalf("Expected 3 requests, given: %d", len(*requests))
+	}
+
+	for _, req := range *requests {
+		if req.To == "48100200001,48100200003" && req.Message != "Your order 1 shipped" {
+			t.Errorf("Expected grouped message, given: %+v", req)
+		}
+	}
+
+	for _, idx := range []string{"a", "b", "c"} {
+		result := report.Results[idx]
+
+		if result.Sms == nil || result.Sms.Idx != idx || result.Err != nil {
+			t.Errorf("Expected %s to be sent, given: %+v", idx, result)
+		}
+	}
+
+	if !errors.Is(report.Results["d"].Err, ErrInvalidRecipient) {
+		t.Errorf("Expected failed batch error, given: %v", report.Results["d"].Err)
+	}
+
+	if report.Results["e"].Err == nil || report.Results["e"].Sms != nil {
+		t.Errorf("Expected render error, given: %+v", report.Results["e"])
+	}
+}
+
+func TestSendPersonalisedRequiresUniqueIdx(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	recipients := []PersonalisedRecipient{
+		{PhoneNumber: "48100200001", Idx: "a"},
+		{PhoneNumber: "48100200002", Idx: "a"},
+	}
+
+	_, err := client.Sms.SendPersonalised(ctx, recipients, NewPlaceholderTemplate("test"), &Sms{}, nil)
+
+	if err != ErrPersonalisedIdx {
+		t.Errorf("Expected ErrPersonalisedIdx, given: %v", err)
+	}
+}
+
+func TestSendPersonalisedMissingParameters(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	recipients := []PersonalisedRecipient{{PhoneNumber: "48100200001", Idx: "a"}}
+
+	if _, err := client.Sms.SendPersonalised(ctx, recipients, NewPlaceholderTemplate("test"), nil, nil); err != ErrMissingSms {
+		t.Errorf("Expected ErrMissingSms, given: %v", err)
+	}
+
+	if _, err := client.Sms.SendPersonalised(ctx, recipients, nil, &Sms{}, nil); err != ErrMissingRenderer {
+		t.Errorf("Expected ErrMissingRenderer, given: %v", err)
+	}
+}
+
+func TestSendPersonalisedMapsNormalisedNumbers(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	client.PhoneNumberPolicy = &PhoneNumberPolicy{DefaultRegion: "PL"}
+
+	requests := serveBulk(mux, func(req *bulkRequest) string {
+		return `{"count":1,"list":[{"id":"1","number":"48500600700","points":0.16}],
+			"invalid_numbers":[{"number":"48500600701","submitted_number":"48500600701","message":"Invalid phone number"}]}`
+	})
+
+	recipients := []PersonalisedRecipient{
+		{PhoneNumber: "+48 500 600 700", Idx: "a"},
+		{PhoneNumber: "500 600 701", Idx: "b"},
+	}
+
+	report, err := client.Sms.SendPersonalised(ctx, recipients, NewPlaceholderTemplate("test"), &Sms{}, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*requests) != 1 || (*requests)[0].To != "48500600700,48500600701" {
+		t.Fatalf("Expected normalised numbers in a single request, given: %+v", *requests)
+	}
+
+	if report.Results["a"].Sms == nil || report.Results["a"].Err != nil {
+		t.Errorf("Expected a to be sent, given: %+v", report.Results["a"])
+	}
+
+	if !errors.Is(report.Results["b"].Err, ErrInvalidRecipient) {
+		t.Errorf("Expected b to be invalid, given: %+v", report.Results["b"])
+	}
+}
@@ -3,6 +3,7 @@ package smsapi
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 )
 
@@ -35,40 +36,52 @@ type AvailableSmsTemplateCollection struct {
 	Collection []*AvailableSmsTemplate `json:"collection"`
 }
 
-func (api *SmsTemplatesApi) List(ctx context.Context) (*SmsTemplateCollection, error) {
+func (api *SmsTemplatesApi) List(ctx context.Context, opts ...CallOption) (*SmsTemplateCollection, error) {
+	ctx = withOperation(ctx, "SmsTemplatesApi.List")
 	result := new(SmsTemplateCollection)
-	err := api.client.Get(ctx, smsTemplatesApiPath, result)
+	err := api.client.Get(ctx, smsTemplatesApiPath, result, opts...)
 	return result, err
 }
 
-func (api *SmsTemplatesApi) Get(ctx context.Context, id string) (*SmsTemplate, error) {
+// All iterates over SMS templates.
+func (api *SmsTemplatesApi) All(ctx context.Context, opts ...CallOption) iter.Seq2[*SmsTemplate, error] {
+	ctx = withOperation(ctx, "SmsTemplatesApi.All")
+	return Paginate[SmsTemplate](ctx, api.client, smsTemplatesApiPath, nil, opts...)
+}
+
+func (api *SmsTemplatesApi) Get(ctx context.Context, id string, opts ...CallOption) (*SmsTemplate, error) {
+	ctx = withOperation(ctx, "SmsTemplatesApi.Get")
 	result := new(SmsTemplate)
 	uri := fmt.Sprintf("%s/%s", smsTemplatesApiPath, id)
-	err := api.client.Get(ctx, uri, result)
+	err := api.client.Get(ctx, uri, result, opts...)
 	return result, err
 }
 
-func (api *SmsTemplatesApi) Create(ctx context.Context, template *SmsTemplate) (*SmsTemplate, error) {
+func (api *SmsTemplatesApi) Create(ctx context.Context, template *SmsTemplate, opts ...CallOption) (*SmsTemplate, error) {
+	ctx = withOperation(ctx, "SmsTemplatesApi.Create")
 	result := new(SmsTemplate)
-	err := api.client.Urlencoded(ctx, http.MethodPost, smsTemplatesApiPath, result, template)
+	err := api.client.Urlencoded(ctx, http.MethodPost, smsTemplatesApiPath, result, template, opts...)
 	return result, err
 }
 
-func (api *SmsTemplatesApi) Update(ctx context.Context, id string, template *SmsTemplate) (*SmsTemplate, error) {
+func (api *SmsTemplatesApi) Update(ctx context.Context, id string, template *SmsTemplate, opts ...CallOption) (*SmsTemplate, error) {
+	ctx = withOperation(ctx, "SmsTemplatesApi.Update")
 	result := new(SmsTemplate)
 	uri := fmt.Sprintf("%s/%s", smsTemplatesApiPath, id)
-	err := api.client.Urlencoded(ctx, http.MethodPut, uri, result, template)
+	err := api.client.Urlencoded(ctx, http.MethodPut, uri, result, template, opts...)
 	return result, err
 }
 
-func (api *SmsTemplatesApi) Delete(ctx context.Context, id string) error {
+func (api *SmsTemplatesApi) Delete(ctx context.Context, id string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "SmsTemplatesApi.Delete")
 	uri := fmt.Sprintf("%s/%s", smsTemplatesApiPath, id)
-	return api.client.Delete(ctx, uri)
+	return api.client.Delete(ctx, uri, opts...)
 }
 
 // ListAvailable returns own templates and (optionally) ones shared with the main account.
-func (api *SmsTemplatesApi) ListAvailable(ctx context.Context) (*AvailableSmsTemplateCollection, error) {
+func (api *SmsTemplatesApi) ListAvailable(ctx context.Context, opts ...CallOption) (*AvailableSmsTemplateCollection, error) {
+	ctx = withOperation(ctx, "SmsTemplatesApi.ListAvailable")
 	result := new(AvailableSmsTemplateCollection)
-	err := api.client.Get(ctx, "/sms/templates/available", result)
+	err := api.client.Get(ctx, "/sms/templates/available", result, opts...)
 	return result, err
 }
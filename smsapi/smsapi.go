@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -41,10 +42,26 @@ func (a *BearerAuth) String() string {
 
 type Client struct {
 	httpClient *http.Client
+	userAgent  string
 
 	BaseUrl *url.URL
 	Auth    *BearerAuth
 
+	// RetryPolicy enables automatic retries of failed requests, nil disables them.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter throttles requests on the client side, nil disables it.
+	RateLimiter *RateLimiter
+
+	middlewares []Middleware
+
+	// Logger logs every API call when set, see LogOptions for redaction of logged data.
+	Logger     *slog.Logger
+	LogOptions LogOptions
+
+	// PhoneNumberPolicy validates phone numbers before requests are made, nil disables it.
+	PhoneNumberPolicy *PhoneNumberPolicy
+
 	Sms          *SmsApi
 	Profile      *ProfileApi
 	Subusers     *SubusersApi
@@ -63,20 +80,45 @@ type Client struct {
 	Vms *VmsApi
 }
 
+// NewClient creates a client without MMS and VMS APIs. Invalid url is silently ignored,
+// use New to get configuration errors reported.
 func NewClient(apiUrl string, accessToken string, httpClient *http.Client) *Client {
-	if httpClient == nil {
-		httpClient = http.DefaultClient
-		httpClient.Timeout = DefaultTimeout
-	}
+	return newLegacyClient(WithBaseURL(apiUrl), WithToken(accessToken), WithHTTPClient(httpClient), withMmsVms(false))
+}
+
+func NewPlClient(accessToken string, httpClient *http.Client) *Client {
+	return NewAllClient(BaseUrlPl, accessToken, httpClient)
+}
+
+func NewAllClient(apiUrl, accessToken string, httpClient *http.Client) *Client {
+	return newLegacyClient(WithBaseURL(apiUrl), WithToken(accessToken), WithHTTPClient(httpClient), withMmsVms(true))
+}
+
+func NewInternationalClient(accessToken string, httpClient *http.Client) *Client {
+	return NewClient(BaseUrlCom, accessToken, httpClient)
+}
+
+func newLegacyClient(opts ...Option) *Client {
+	o, _ := applyOptions(opts)
 
-	baseUrl, _ := url.Parse(apiUrl)
+	baseUrl, _ := url.Parse(o.resolveBaseUrl())
 
-	auth := &BearerAuth{AccessToken: accessToken}
+	return newClient(o, baseUrl)
+}
 
+func newClient(o *options, baseUrl *url.URL) *Client {
 	c := &Client{
-		httpClient: httpClient,
-		BaseUrl:    baseUrl,
-		Auth:       auth,
+		httpClient:  o.resolveHttpClient(),
+		userAgent:   o.userAgent(),
+		BaseUrl:     baseUrl,
+		Auth:        &BearerAuth{AccessToken: o.token},
+		RetryPolicy: o.retryPolicy,
+		RateLimiter: o.rateLimiter,
+		middlewares: o.middlewares,
+		Logger:      o.logger,
+		LogOptions:  o.logOptions,
+
+		PhoneNumberPolicy: o.phoneNumberPolicy,
 	}
 
 	c.Sms = &SmsApi{client: c}
@@ -93,24 +135,12 @@ func NewClient(apiUrl string, accessToken string, httpClient *http.Client) *Clie
 	c.SmsTemplates = &SmsTemplatesApi{client: c}
 	c.Shipment = &ShipmentApi{client: c}
 
-	return c
-}
-
-func NewPlClient(accessToken string, httpClient *http.Client) *Client {
-	return NewAllClient(BaseUrlPl, accessToken, httpClient)
-}
-
-func NewAllClient(apiUrl, accessToken string, httpClient *http.Client) *Client {
-	smsapiClient := NewClient(apiUrl, accessToken, httpClient)
-
-	smsapiClient.Mms = &MmsApi{client: smsapiClient}
-	smsapiClient.Vms = &VmsApi{client: smsapiClient}
-
-	return smsapiClient
-}
+	if o.resolveMmsVms() {
+		c.Mms = &MmsApi{client: c}
+		c.Vms = &VmsApi{client: c}
+	}
 
-func NewInternationalClient(accessToken string, httpClient *http.Client) *Client {
-	return NewClient(BaseUrlCom, accessToken, httpClient)
+	return c
 }
 
 func (client *Client) NewUrlencodedRequest(method, path string, body interface{}) (*http.Request, error) {
@@ -156,44 +186,225 @@ func (client *Client) NewRequest(method, path string, buf io.Reader, contentType
 
 	req.Header.Set("Content-Type", string(contentType))
 	req.Header.Set("Authorization", client.Auth.String())
-	req.Header.Set("User-Agent", fmt.Sprintf("%s/%s", Name, Version))
+	req.Header.Set("User-Agent", client.userAgentString())
 
 	return req, nil
 }
 
-func (client *Client) executeRequest(ctx context.Context, req *http.Request, v interface{}) error {
+func (client *Client) userAgentString() string {
+	if client.userAgent == "" {
+		return fmt.Sprintf("%s/%s", Name, Version)
+	}
+
+	return client.userAgent
+}
+
+func (client *Client) executeRequest(ctx context.Context, req *http.Request, v interface{}, opts ...CallOption) error {
+	meta := new(Response)
+	info := client.newRequestInfo(ctx, req)
+	callOptions := callOptionsFromContext(ctx).with(opts)
+
+	ctx, cancel, err := callOptions.apply(ctx, client, req)
+	defer cancel()
+
+	if err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, requestInfoKey{}, info)
+	ctx = context.WithValue(ctx, responseKey{}, meta)
 	req = req.WithContext(ctx)
 
+	requestBody := client.requestBodyForLog(req)
+	started := time.Now()
+
+	resp, err := client.roundTrip(req)
+
+	meta.Latency = time.Since(started)
+
+	if err == nil {
+		defer resp.Body.Close()
+
+		var responseData []byte
+
+		responseData, err = client.CheckError(resp)
+
+		meta.fill(resp, responseData)
+
+		if err == nil {
+			err = decodeResponse(resp, responseData, v)
+		}
+	}
+
+	client.logCall(req, requestBody, meta, err)
+
+	callOptions.store(meta)
+
+	return err
+}
+
+// roundTrip passes the request through registered middlewares, the first one
+// registered being the outermost.
+func (client *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	next := client.retryRoundTrip
+
+	for i := len(client.middlewares) - 1; i >= 0; i-- {
+		next = client.middlewares[i](next)
+	}
+
+	return next(req)
+}
+
+// retryRoundTrip executes the request according to RetryPolicy. The returned
+// response body is buffered, so it can be read regardless of retries.
+func (client *Client) retryRoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	policy := client.RetryPolicy
+
+	body, err := bufferRequestBody(req, policy.maxAttempts() > 1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	replaySafe := isReplaySafe(req, body)
+
+	for attempt := 1; ; attempt++ {
+		if meta, ok := ctx.Value(responseKey{}).(*Response); ok {
+			meta.Attempts++
+		}
+
+		resp, err := client.attempt(req, body)
+
+		if err == nil {
+			err = client.checkBufferedError(resp)
+		}
+
+		if err == nil || attempt >= policy.maxAttempts() || !replaySafe || !policy.isRetryable(err) {
+			return resp, responseError(resp, err)
+		}
+
+		delay, ok := retryAfter(resp)
+
+		if !ok {
+			delay = policy.backoff(attempt)
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, responseError(resp, err)
+		}
+
+		if sleep(ctx, delay) != nil {
+			return resp, responseError(resp, err)
+		}
+	}
+}
+
+// responseError drops API errors, which are reported from the response itself.
+func responseError(resp *http.Response, err error) error {
+	if resp != nil {
+		return nil
+	}
+
+	return err
+}
+
+// attempt sends the request once, waiting for the rate limiter first.
+func (client *Client) attempt(req *http.Request, body []byte) (*http.Response, error) {
+	if client.RateLimiter == nil {
+		return client.do(req, body)
+	}
+
+	area := AreaUnknown
+
+	if info, ok := RequestInfoFromContext(req.Context()); ok {
+		area = info.Area
+	}
+
+	if err := client.RateLimiter.Wait(req.Context(), area); err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(req, body)
+
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		delay, _ := retryAfter(resp)
+		client.RateLimiter.Throttle(area, delay)
+	}
+
+	return resp, err
+}
+
+// checkBufferedError runs CheckError leaving the response body readable.
+func (client *Client) checkBufferedError(resp *http.Response) error {
+	responseData, err := client.CheckError(resp)
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(responseData))
+
+	return err
+}
+
+func (client *Client) do(req *http.Request, body []byte) (*http.Response, error) {
+	if body != nil {
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
 	resp, err := client.httpClient.Do(req)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer resp.Body.Close()
 
-	responseData, err := client.CheckError(resp)
+	responseData, err := ioutil.ReadAll(resp.Body)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if v == nil {
+	resp.Body = ioutil.NopCloser(bytes.NewReader(responseData))
+
+	return resp, nil
+}
+
+func decodeResponse(resp *http.Response, responseData []byte, v interface{}) error {
+	if v == nil || len(bytes.TrimSpace(responseData)) == 0 {
 		return nil
 	}
 
+	if !json.Valid(responseData) {
+		return newUnexpectedResponseError(resp, responseData)
+	}
+
 	responseDataReader := bytes.NewReader(responseData)
 
-	err = json.NewDecoder(responseDataReader).Decode(v)
+	return json.NewDecoder(responseDataReader).Decode(v)
+}
 
-	return err
+// bufferRequestBody reads the request body into memory so it can be replayed on retry.
+func bufferRequestBody(req *http.Request, replayable bool) ([]byte, error) {
+	if !replayable || req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+
+	req.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
 }
 
 var legacyQueryParams = struct {
 	Format string `url:"format"`
 }{Format: "json"}
 
-func (client *Client) LegacyGet(ctx context.Context, path string, result interface{}) error {
+func (client *Client) LegacyGet(ctx context.Context, path string, result interface{}, opts ...CallOption) error {
 	path, _ = addQueryParams(path, legacyQueryParams)
 
 	req, err := client.NewJsonRequest("GET", path, nil)
@@ -202,10 +413,10 @@ func (client *Client) LegacyGet(ctx context.Context, path string, result interfa
 		return err
 	}
 
-	return client.executeRequest(ctx, req, result)
+	return client.executeRequest(ctx, req, result, opts...)
 }
 
-func (client *Client) LegacyPost(ctx context.Context, path string, result interface{}, data interface{}) error {
+func (client *Client) LegacyPost(ctx context.Context, path string, result interface{}, data interface{}, opts ...CallOption) error {
 	path, _ = addQueryParams(path, legacyQueryParams)
 
 	req, err := client.NewJsonRequest("POST", path, data)
@@ -214,82 +425,106 @@ func (client *Client) LegacyPost(ctx context.Context, path string, result interf
 		return err
 	}
 
-	return client.executeRequest(ctx, req, result)
+	return client.executeRequest(ctx, req, result, opts...)
 }
 
-func (client *Client) Get(ctx context.Context, path string, result interface{}) error {
+func (client *Client) Get(ctx context.Context, path string, result interface{}, opts ...CallOption) error {
 	req, err := client.NewJsonRequest("GET", path, nil)
 
 	if err != nil {
 		return err
 	}
 
-	return client.executeRequest(ctx, req, result)
+	return client.executeRequest(ctx, req, result, opts...)
 }
 
-func (client *Client) Urlencoded(ctx context.Context, method, path string, result interface{}, data interface{}) error {
+func (client *Client) Urlencoded(ctx context.Context, method, path string, result interface{}, data interface{}, opts ...CallOption) error {
 	req, err := client.NewUrlencodedRequest(method, path, data)
 
 	if err != nil {
 		return err
 	}
 
-	return client.executeRequest(ctx, req, result)
+	return client.executeRequest(ctx, req, result, opts...)
 }
 
-func (client *Client) Post(ctx context.Context, path string, result interface{}, data interface{}) error {
+func (client *Client) Post(ctx context.Context, path string, result interface{}, data interface{}, opts ...CallOption) error {
 	req, err := client.NewJsonRequest("POST", path, data)
 
 	if err != nil {
 		return err
 	}
 
-	return client.executeRequest(ctx, req, result)
+	return client.executeRequest(ctx, req, result, opts...)
 }
 
-func (client *Client) Put(ctx context.Context, path string, result interface{}, data interface{}) error {
+func (client *Client) Put(ctx context.Context, path string, result interface{}, data interface{}, opts ...CallOption) error {
 	req, err := client.NewJsonRequest("PUT", path, data)
 
 	if err != nil {
 		return err
 	}
 
-	return client.executeRequest(ctx, req, result)
+	return client.executeRequest(ctx, req, result, opts...)
 }
 
-func (client *Client) PostRaw(ctx context.Context, path string, body io.Reader, contentType ContentType, result interface{}) error {
+func (client *Client) PostRaw(ctx context.Context, path string, body io.Reader, contentType ContentType, result interface{}, opts ...CallOption) error {
 	req, err := client.NewRequest("POST", path, body, contentType)
 
 	if err != nil {
 		return err
 	}
 
-	return client.executeRequest(ctx, req, result)
+	return client.executeRequest(ctx, req, result, opts...)
 }
 
-func (client *Client) Delete(ctx context.Context, path string) error {
+func (client *Client) Delete(ctx context.Context, path string, opts ...CallOption) error {
 	req, err := client.NewJsonRequest("DELETE", path, nil)
 
 	if err != nil {
 		return err
 	}
 
-	return client.executeRequest(ctx, req, nil)
+	return client.executeRequest(ctx, req, nil, opts...)
 }
 
+// CheckError reads the response body and reports API errors. Only 2xx responses
+// are successful, and even those may carry a legacy API error code. Failed responses
+// without a JSON body are reported as EmptyResponseError or UnexpectedResponseError.
 func (client *Client) CheckError(r *http.Response) ([]byte, error) {
-	errorResponse := &ErrorResponse{}
-
 	responseData, err := ioutil.ReadAll(r.Body)
 
-	if err == nil && responseData != nil {
-		json.Unmarshal(responseData, errorResponse)
+	if err != nil {
+		return responseData, err
+	}
+
+	success := 200 <= r.StatusCode && r.StatusCode < 300
+
+	if len(bytes.TrimSpace(responseData)) == 0 {
+		if success {
+			return responseData, nil
+		}
+
+		return responseData, &EmptyResponseError{Status: r.StatusCode}
 	}
 
-	if httpCode := r.StatusCode; errorResponse.Code == 0 && 200 <= httpCode && httpCode <= 300 {
+	errorResponse := &ErrorResponse{}
+	decodeErr := json.Unmarshal(responseData, errorResponse)
+
+	if success {
+		if decodeErr == nil && errorResponse.Code != 0 {
+			errorResponse.Status = r.StatusCode
+
+			return responseData, errorResponse
+		}
+
 		return responseData, nil
 	}
 
+	if !json.Valid(responseData) {
+		return responseData, newUnexpectedResponseError(r, responseData)
+	}
+
 	errorResponse.Status = r.StatusCode
 
 	return responseData, errorResponse
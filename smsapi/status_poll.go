@@ -0,0 +1,244 @@
+package smsapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultStatusPollBatchSize   = 100
+	DefaultStatusPollInterval    = 5 * time.Second
+	DefaultStatusPollMaxInterval = time.Minute
+)
+
+// StatusPollOptions configures WaitForFinalStatus, nil means DefaultStatusPollBatchSize ids
+// per request polled every DefaultStatusPollInterval, doubled up to DefaultStatusPollMaxInterval.
+type StatusPollOptions[T any] struct {
+	// BatchSize is the number of ids checked in a single request.
+	BatchSize int
+	// Interval is the delay before the first poll, it doubles after every poll.
+	Interval    time.Duration
+	MaxInterval time.Duration
+	// Updates receives every response with a changed status, it is not closed.
+	Updates chan<- *T
+}
+
+// MessagesNotFoundError lists ids of messages the API did not return, e.g. unknown or
+// expired ones. It matches ErrNotFound.
+type MessagesNotFoundError struct {
+	Ids []string
+}
+
+func (e *MessagesNotFoundError) Error() string {
+	return "smsapi: messages not found: " + strings.Join(e.Ids, ",")
+}
+
+func (e *MessagesNotFoundError) Unwrap() error {
+	return ErrNotFound
+}
+
+// WaitForFinalStatus polls statuses of sent messages until all of them are final or the
+// context is done. It returns final responses by message id, along with the context error
+// when not all messages reached a final status in time.
+//
+// Messages the API does not return, or rejects as not found, are no longer polled and are
+// reported with MessagesNotFoundError, joined with the context error if any.
+func (smsApi *SmsApi) WaitForFinalStatus(ctx context.Context, ids []string, options *StatusPollOptions[SmsResponse], opts ...CallOption) (map[string]*SmsResponse, error) {
+	ctx = withOperation(ctx, "SmsApi.WaitForFinalStatus")
+
+	fetch := func(ctx context.Context, ids string) ([]*SmsResponse, error) {
+		result, err := smsApi.Get(ctx, ids, opts...)
+
+		return result.Collection, err
+	}
+
+	return waitForFinalStatus(ctx, ids, options, fetch, func(r *SmsResponse) (string, MessageStatus) {
+		return r.Id, r.Status
+	})
+}
+
+// WaitForFinalStatus polls statuses of sent messages, see SmsApi.WaitForFinalStatus.
+func (mmsApi *MmsApi) WaitForFinalStatus(ctx context.Context, ids []string, options *StatusPollOptions[MmsResponse], opts ...CallOption) (map[string]*MmsResponse, error) {
+	ctx = withOperation(ctx, "MmsApi.WaitForFinalStatus")
+
+	fetch := func(ctx context.Context, ids string) ([]*MmsResponse, error) {
+		result, err := mmsApi.Get(ctx, ids, opts...)
+
+		return result.Collection, err
+	}
+
+	return waitForFinalStatus(ctx, ids, options, fetch, func(r *MmsResponse) (string, MessageStatus) {
+		return r.Id, r.Status
+	})
+}
+
+// WaitForFinalStatus polls statuses of sent messages, see SmsApi.WaitForFinalStatus.
+func (vmsApi *VmsApi) WaitForFinalStatus(ctx context.Context, ids []string, options *StatusPollOptions[VmsResponse], opts ...CallOption) (map[string]*VmsResponse, error) {
+	ctx = withOperation(ctx, "VmsApi.WaitForFinalStatus")
+
+	fetch := func(ctx context.Context, ids string) ([]*VmsResponse, error) {
+		result, err := vmsApi.Get(ctx, ids, opts...)
+
+		return result.Collection, err
+	}
+
+	return waitForFinalStatus(ctx, ids, options, fetch, func(r *VmsResponse) (string, MessageStatus) {
+		return r.Id, r.Status
+	})
+}
+
+func waitForFinalStatus[T any](
+	ctx context.Context,
+	ids []string,
+	options *StatusPollOptions[T],
+	fetch func(ctx context.Context, ids string) ([]*T, error),
+	status func(*T) (string, MessageStatus),
+) (map[string]*T, error) {
+	o := options.withDefaults()
+
+	final := make(map[string]*T, len(ids))
+	last := map[string]MessageStatus{}
+	pending := append([]string(nil), ids...)
+	interval := o.Interval
+	missing := map[string]bool{}
+	notFound := &MessagesNotFoundError{}
+
+	result := func(err error) (map[string]*T, error) {
+		if len(notFound.Ids) > 0 {
+			return final, errors.Join(err, notFound)
+		}
+
+		return final, err
+	}
+
+	for len(pending) > 0 {
+		timer := time.NewTimer(interval)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+
+			return result(ctx.Err())
+		}
+
+		for start := 0; start < len(pending); start += o.BatchSize {
+			batch := pending[start:min(start+o.BatchSize, len(pending))]
+
+			responses, err := fetchStatuses(ctx, batch, fetch)
+
+			if err != nil {
+				// The deadline may hit during the request, report it as such.
+				if ctx.Err() != nil {
+					return result(ctx.Err())
+				}
+
+				return result(err)
+			}
+
+			returned := make(map[string]bool, len(responses))
+
+			for _, response := range responses {
+				id, s := status(response)
+				returned[id] = true
+
+				if s != last[id] {
+					last[id] = s
+
+					if err := sendUpdate(ctx, o.Updates, response); err != nil {
+						return final, err
+					}
+				}
+
+				if s.IsFinal() {
+					final[id] = response
+				}
+			}
+
+			for _, id := range batch {
+				if !returned[id] && !missing[id] {
+					missing[id] = true
+					notFound.Ids = append(notFound.Ids, id)
+				}
+			}
+		}
+
+		var next []string
+
+		for _, id := range pending {
+			if _, ok := final[id]; !ok && !missing[id] {
+				next = append(next, id)
+			}
+		}
+
+		pending = next
+		interval = min(interval*2, o.MaxInterval)
+	}
+
+	return result(nil)
+}
+
+// fetchStatuses fetches statuses of a batch. The API rejects the whole batch when one of
+// the ids is not found, the ids are then fetched one by one and the unknown ones omitted.
+func fetchStatuses[T any](ctx context.Context, batch []string, fetch func(ctx context.Context, ids string) ([]*T, error)) ([]*T, error) {
+	responses, err := fetch(ctx, strings.Join(batch, ","))
+
+	if !errors.Is(err, ErrNotFound) {
+		return responses, err
+	}
+
+	if len(batch) == 1 {
+		return nil, nil
+	}
+
+	var result []*T
+
+	for _, id := range batch {
+		responses, err := fetchStatuses(ctx, []string{id}, fetch)
+
+		if err != nil {
+			return result, err
+		}
+
+		result = append(result, responses...)
+	}
+
+	return result, nil
+}
+
+func sendUpdate[T any](ctx context.Context, updates chan<- *T, response *T) error {
+	if updates == nil {
+		return nil
+	}
+
+	select {
+	case updates <- response:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (o *StatusPollOptions[T]) withDefaults() StatusPollOptions[T] {
+	result := StatusPollOptions[T]{}
+
+	if o != nil {
+		result = *o
+	}
+
+	if result.BatchSize < 1 {
+		result.BatchSize = DefaultStatusPollBatchSize
+	}
+
+	if result.Interval <= 0 {
+		result.Interval = DefaultStatusPollInterval
+	}
+
+	if result.MaxInterval < result.Interval {
+		result.MaxInterval = max(DefaultStatusPollMaxInterval, result.Interval)
+	}
+
+	return result
+}
@@ -0,0 +1,175 @@
+package smsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWaitForFinalStatus(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	// Every poll moves messages one step further, message "2" never reaches a final status
+	// before "1" and "3" do.
+	steps := map[string][]string{
+		"1": {"QUEUE", "SENT", "DELIVERED"},
+		"2": {"QUEUE", "SENT", "SENT", "UNDELIVERED"},
+		"3": {"EXPIRED"},
+	}
+
+	var mu sync.Mutex
+	var polls []string
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ids := r.URL.Query().Get("status")
+		polls = append(polls, ids)
+
+		var items []string
+
+		for _, id := range strings.Split(ids, ",") {
+			status := steps[id][0]
+
+			if len(steps[id]) > 1 {
+				steps[id] = steps[id][1:]
+			}
+
+			items = append(items, fmt.Sprintf(`{"id":"%s","status":"%s"}`, id, status))
+		}
+
+		fmt.Fprintf(w, `{"count":%d,"list":[%s]}`, len(items), strings.Join(items, ","))
+	})
+
+	updates := make(chan *SmsResponse, 20)
+
+	options := &StatusPollOptions[SmsResponse]{BatchSize: 2, Interval: time.Millisecond, Updates: updates}
+
+	result, err := client.Sms.WaitForFinalStatus(ctx, []string{"1", "2", "3"}, options)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result["1"].Status != MessageStatusDelivered || result["2"].Status != MessageStatusUndelivered || result["3"].Status != MessageStatusExpired {
+		t.Errorf("Unexpected final statuses: %+v %+v %+v", result["1"], result["2"], result["3"])
+	}
+
+	expected := []string{"1,2", "3", "1,2", "1,2", "2"}
+
+	if strings.Join(polls, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected polls: %v given: %v", expected, polls)
+	}
+
+	close(updates)
+
+	count := 0
+
+	for range updates {
+		count++
+	}
+
+	if count != 7 {
+		t.Errorf("Expected 7 status changes, given: %d", count)
+	}
+}
+
+func TestWaitForFinalStatusContextDone(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"count":2,"list":[{"id":"1","status":"DELIVERED"},{"id":"2","status":"QUEUE"}]}`)
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	options := &StatusPollOptions[SmsResponse]{Interval: time.Millisecond, MaxInterval: 5 * time.Millisecond}
+
+	result, err := client.Sms.WaitForFinalStatus(ctx, []string{"1", "2"}, options)
+
+	if !errors.Is(err, context.DeadlineExceeded) || len(result) != 1 || result["1"] == nil {
+		t.Errorf("Expected partial result with deadline error, given: %v %v", result, err)
+	}
+}
+
+func TestWaitForFinalStatusDeadlineDuringFetch(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+
+	options := &StatusPollOptions[SmsResponse]{Interval: time.Millisecond}
+
+	_, err := client.Sms.WaitForFinalStatus(ctx, []string{"1"}, options)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context error, given: %#v", err)
+	}
+}
+
+func TestWaitForFinalStatusNotFound(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	var polls []string
+
+	mux.HandleFunc("/sms.do", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ids := r.URL.Query().Get("status")
+		polls = append(polls, ids)
+
+		// "2" is unknown and rejects its batch, "4" is silently left out.
+		if strings.Contains(ids, "2") {
+			fmt.Fprint(w, `{"error":301,"message":"Not exists ID message"}`)
+
+			return
+		}
+
+		var items []string
+
+		for _, id := range strings.Split(ids, ",") {
+			if id != "4" {
+				items = append(items, fmt.Sprintf(`{"id":"%s","status":"DELIVERED"}`, id))
+			}
+		}
+
+		fmt.Fprintf(w, `{"count":%d,"list":[%s]}`, len(items), strings.Join(items, ","))
+	})
+
+	options := &StatusPollOptions[SmsResponse]{BatchSize: 2, Interval: time.Millisecond}
+
+	result, err := client.Sms.WaitForFinalStatus(ctx, []string{"1", "2", "3", "4"}, options)
+
+	var notFound *MessagesNotFoundError
+
+	if !errors.As(err, &notFound) || !errors.Is(err, ErrNotFound) || strings.Join(notFound.Ids, ",") != "2,4" {
+		t.Fatalf("Expected messages 2 and 4 not to be found, given: %v", err)
+	}
+
+	if len(result) != 2 || result["1"] == nil || result["3"] == nil {
+		t.Errorf("Expected other messages to be polled, given: %v", result)
+	}
+
+	expected := []string{"1,2", "1", "2", "3,4"}
+
+	if strings.Join(polls, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected polls: %v given: %v", expected, polls)
+	}
+}
@@ -3,6 +3,7 @@ package smsapi
 import (
 	"context"
 	"fmt"
+	"iter"
 )
 
 const (
@@ -48,52 +49,69 @@ type UserCollectionFilters struct {
 	Query string `url:"q,omitempty"`
 }
 
-func (accountApi *SubusersApi) GetUser(ctx context.Context, id string) (*UserResponse, error) {
+func (accountApi *SubusersApi) GetUser(ctx context.Context, id string, opts ...CallOption) (*UserResponse, error) {
+	ctx = withOperation(ctx, "SubusersApi.GetUser")
+
 	var result = new(UserResponse)
 
 	uri := fmt.Sprintf("%s/%s", usersApiPath, id)
 
-	err := accountApi.client.Get(ctx, uri, result)
+	err := accountApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
-func (accountApi *SubusersApi) CreateUser(ctx context.Context, user *User) (*UserResponse, error) {
+func (accountApi *SubusersApi) CreateUser(ctx context.Context, user *User, opts ...CallOption) (*UserResponse, error) {
+	ctx = withOperation(ctx, "SubusersApi.CreateUser")
+
 	var result = new(UserResponse)
 
-	err := accountApi.client.Post(ctx, usersApiPath, result, user)
+	err := accountApi.client.Post(ctx, usersApiPath, result, user, opts...)
 
 	return result, err
 }
 
-func (accountApi *SubusersApi) UpdateUser(ctx context.Context, id string, user *User) (*UserResponse, error) {
+func (accountApi *SubusersApi) UpdateUser(ctx context.Context, id string, user *User, opts ...CallOption) (*UserResponse, error) {
+	ctx = withOperation(ctx, "SubusersApi.UpdateUser")
+
 	var result = new(UserResponse)
 
 	uri := fmt.Sprintf("%s/%s", usersApiPath, id)
 
-	err := accountApi.client.Put(ctx, uri, result, user)
+	err := accountApi.client.Put(ctx, uri, result, user, opts...)
 
 	return result, err
 }
 
-func (accountApi *SubusersApi) DeleteUser(ctx context.Context, id string) error {
+func (accountApi *SubusersApi) DeleteUser(ctx context.Context, id string, opts ...CallOption) error {
+	ctx = withOperation(ctx, "SubusersApi.DeleteUser")
+
 	uri := fmt.Sprintf("%s/%s", usersApiPath, id)
 
-	err := accountApi.client.Delete(ctx, uri)
+	err := accountApi.client.Delete(ctx, uri, opts...)
 
 	return err
 }
 
-func (accountApi *SubusersApi) ListUsers(ctx context.Context, filters *UserCollectionFilters) (*UserCollectionResponse, error) {
+func (accountApi *SubusersApi) ListUsers(ctx context.Context, filters *UserCollectionFilters, opts ...CallOption) (*UserCollectionResponse, error) {
+	ctx = withOperation(ctx, "SubusersApi.ListUsers")
+
 	var result = new(UserCollectionResponse)
 
 	uri, _ := addQueryParams(usersApiPath, filters)
 
-	err := accountApi.client.Get(ctx, uri, result)
+	err := accountApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
+// All iterates over subusers matching the filters.
+func (accountApi *SubusersApi) All(ctx context.Context, filters *UserCollectionFilters, opts ...CallOption) iter.Seq2[*UserResponse, error] {
+	ctx = withOperation(ctx, "SubusersApi.All")
+
+	return Paginate[UserResponse](ctx, accountApi.client, usersApiPath, filters, opts...)
+}
+
 type SubuserAccess struct {
 	Access    string   `json:"access"`
 	Senders   []string `json:"senders,omitempty"`
@@ -108,52 +126,62 @@ type SubuserSharesResponse struct {
 }
 
 // GetShares returns the subuser shares overview (sendernames, blacklist, templates).
-func (accountApi *SubusersApi) GetShares(ctx context.Context, id string) (*SubuserSharesResponse, error) {
+func (accountApi *SubusersApi) GetShares(ctx context.Context, id string, opts ...CallOption) (*SubuserSharesResponse, error) {
+	ctx = withOperation(ctx, "SubusersApi.GetShares")
+
 	var result = new(SubuserSharesResponse)
 
 	uri := fmt.Sprintf("%s/%s/shares", usersApiPath, id)
 
-	err := accountApi.client.Get(ctx, uri, result)
+	err := accountApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
 // GetSendernamesAccess returns the subuser's native sendernames access configuration.
-func (accountApi *SubusersApi) GetSendernamesAccess(ctx context.Context, id string) (*SubuserAccess, error) {
+func (accountApi *SubusersApi) GetSendernamesAccess(ctx context.Context, id string, opts ...CallOption) (*SubuserAccess, error) {
+	ctx = withOperation(ctx, "SubusersApi.GetSendernamesAccess")
+
 	var result = new(SubuserAccess)
 
 	uri := fmt.Sprintf("%s/%s/shares/sendernames", usersApiPath, id)
 
-	err := accountApi.client.Get(ctx, uri, result)
+	err := accountApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
 // UpdateSendernamesAccess updates the subuser's native sendernames access configuration.
-func (accountApi *SubusersApi) UpdateSendernamesAccess(ctx context.Context, id string, access *SubuserAccess) error {
+func (accountApi *SubusersApi) UpdateSendernamesAccess(ctx context.Context, id string, access *SubuserAccess, opts ...CallOption) error {
+	ctx = withOperation(ctx, "SubusersApi.UpdateSendernamesAccess")
+
 	uri := fmt.Sprintf("%s/%s/shares/sendernames", usersApiPath, id)
 
-	return accountApi.client.Put(ctx, uri, nil, access)
+	return accountApi.client.Put(ctx, uri, nil, access, opts...)
 }
 
 // GetTemplatesAccess returns the subuser's native templates access configuration.
-func (accountApi *SubusersApi) GetTemplatesAccess(ctx context.Context, id string) (*SubuserAccess, error) {
+func (accountApi *SubusersApi) GetTemplatesAccess(ctx context.Context, id string, opts ...CallOption) (*SubuserAccess, error) {
+	ctx = withOperation(ctx, "SubusersApi.GetTemplatesAccess")
+
 	var result = new(SubuserAccess)
 
 	uri := fmt.Sprintf("%s/%s/shares/templates", usersApiPath, id)
 
-	err := accountApi.client.Get(ctx, uri, result)
+	err := accountApi.client.Get(ctx, uri, result, opts...)
 
 	return result, err
 }
 
 // UpdateTemplatesAccess updates the subuser's native templates access configuration.
-func (accountApi *SubusersApi) UpdateTemplatesAccess(ctx context.Context, id string, access *SubuserAccess) (*SubuserAccess, error) {
+func (accountApi *SubusersApi) UpdateTemplatesAccess(ctx context.Context, id string, access *SubuserAccess, opts ...CallOption) (*SubuserAccess, error) {
+	ctx = withOperation(ctx, "SubusersApi.UpdateTemplatesAccess")
+
 	var result = new(SubuserAccess)
 
 	uri := fmt.Sprintf("%s/%s/shares/templates", usersApiPath, id)
 
-	err := accountApi.client.Put(ctx, uri, result, access)
+	err := accountApi.client.Put(ctx, uri, result, access, opts...)
 
 	return result, err
 }
@@ -27,14 +27,14 @@ type Vms struct {
 }
 
 type VmsResponse struct {
-	Id              string     `json:"id,omitempty"`
-	Points          Points     `json:"points,omitempty"`
-	Number          string     `json:"number,omitempty"`
-	DateSent        *Timestamp `json:"date_sent,omitempty"`
-	SubmittedNumber string     `json:"submitted_number,omitempty"`
-	Status          string     `json:"status,omitempty"`
-	Idx             string     `json:"idx,omitempty"`
-	Error           string     `json:"error,omitempty"`
+	Id              string        `json:"id,omitempty"`
+	Points          Points        `json:"points,omitempty"`
+	Number          string        `json:"number,omitempty"`
+	DateSent        *Timestamp    `json:"date_sent,omitempty"`
+	SubmittedNumber string        `json:"submitted_number,omitempty"`
+	Status          MessageStatus `json:"status,omitempty"`
+	Idx             string        `json:"idx,omitempty"`
+	Error           string        `json:"error,omitempty"`
 }
 
 type VmsCollectionResponse struct {
@@ -43,25 +43,31 @@ type VmsCollectionResponse struct {
 	Collection []*VmsResponse `json:"list"`
 }
 
-func (vmsApi *VmsApi) SendRaw(ctx context.Context, vms *Vms) (*VmsCollectionResponse, error) {
+func (vmsApi *VmsApi) SendRaw(ctx context.Context, vms *Vms, opts ...CallOption) (*VmsCollectionResponse, error) {
+	ctx = withOperation(ctx, "VmsApi.SendRaw")
+
 	var result = new(VmsCollectionResponse)
 
-	err := vmsApi.client.LegacyPost(ctx, vmsApiPath, result, vms)
+	err := vmsApi.client.LegacyPost(ctx, vmsApiPath, result, vms, opts...)
 
 	return result, err
 }
 
-func (vmsApi *VmsApi) Send(ctx context.Context, to, message, from string) (*VmsCollectionResponse, error) {
+func (vmsApi *VmsApi) Send(ctx context.Context, to, message, from string, opts ...CallOption) (*VmsCollectionResponse, error) {
+	ctx = withOperation(ctx, "VmsApi.Send")
+
 	vms := &Vms{
 		To:   to,
 		Tts:  message,
 		From: from,
 	}
 
-	return vmsApi.SendRaw(ctx, vms)
+	return vmsApi.SendRaw(ctx, vms, opts...)
 }
 
-func (vmsApi *VmsApi) Schedule(ctx context.Context, to, message, from string, sendAt *Timestamp) (*VmsCollectionResponse, error) {
+func (vmsApi *VmsApi) Schedule(ctx context.Context, to, message, from string, sendAt *Timestamp, opts ...CallOption) (*VmsCollectionResponse, error) {
+	ctx = withOperation(ctx, "VmsApi.Schedule")
+
 	vms := &Vms{
 		To:   to,
 		Tts:  message,
@@ -69,17 +75,19 @@ func (vmsApi *VmsApi) Schedule(ctx context.Context, to, message, from string, se
 		Date: sendAt,
 	}
 
-	return vmsApi.SendRaw(ctx, vms)
+	return vmsApi.SendRaw(ctx, vms, opts...)
 }
 
-func (vmsApi *VmsApi) SendToGroup(ctx context.Context, group, message, from string) (*VmsCollectionResponse, error) {
+func (vmsApi *VmsApi) SendToGroup(ctx context.Context, group, message, from string, opts ...CallOption) (*VmsCollectionResponse, error) {
+	ctx = withOperation(ctx, "VmsApi.SendToGroup")
+
 	vms := &Vms{
 		Group: group,
 		Tts:   message,
 		From:  from,
 	}
 
-	return vmsApi.SendRaw(ctx, vms)
+	return vmsApi.SendRaw(ctx, vms, opts...)
 }
 
 type VmsRemoveResponse struct {
@@ -90,19 +98,23 @@ type VmsRemoveResponse struct {
 	} `json:"list"`
 }
 
-func (vmsApi *VmsApi) RemoveScheduled(ctx context.Context, id string) (*VmsRemoveResponse, error) {
+func (vmsApi *VmsApi) RemoveScheduled(ctx context.Context, id string, opts ...CallOption) (*VmsRemoveResponse, error) {
+	ctx = withOperation(ctx, "VmsApi.RemoveScheduled")
+
 	var result = new(VmsRemoveResponse)
 
 	payload := struct {
 		SchDel string `json:"sch_del"`
 	}{SchDel: id}
 
-	err := vmsApi.client.LegacyPost(ctx, vmsApiPath, result, payload)
+	err := vmsApi.client.LegacyPost(ctx, vmsApiPath, result, payload, opts...)
 
 	return result, err
 }
 
-func (vmsApi *VmsApi) Get(ctx context.Context, id string) (*VmsCollectionResponse, error) {
+func (vmsApi *VmsApi) Get(ctx context.Context, id string, opts ...CallOption) (*VmsCollectionResponse, error) {
+	ctx = withOperation(ctx, "VmsApi.Get")
+
 	var result = new(VmsCollectionResponse)
 
 	v := struct {
@@ -111,7 +123,7 @@ func (vmsApi *VmsApi) Get(ctx context.Context, id string) (*VmsCollectionRespons
 
 	uri, _ := addQueryParams(vmsApiPath, v)
 
-	err := vmsApi.client.LegacyGet(ctx, uri, result)
+	err := vmsApi.client.LegacyGet(ctx, uri, result, opts...)
 
 	return result, err
 }
@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smsapi/smsapi-go/smsapi"
+)
+
+var ErrMissingMessageId = errors.New("webhook: missing MsgId parameter")
+
+// DeliveryReport is a delivery report (DLR) of a sent SMS, MMS or VMS.
+type DeliveryReport struct {
+	Channel   Channel
+	MessageId string
+	Status    smsapi.MessageStatus
+	// StatusName is the status name sent along with the numeric status.
+	StatusName string
+	Idx        string
+	DoneDate   *smsapi.Timestamp
+	Username   string
+	Points     smsapi.Points
+	From       string
+	To         string
+	Mcc        string
+	Mnc        string
+}
+
+// ParseDeliveryReports parses delivery reports from query or form parameters of a callback
+// request. A single request may carry several reports as comma separated values.
+func ParseDeliveryReports(r *http.Request, channel Channel) ([]*DeliveryReport, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	ids := r.Form.Get("MsgId")
+
+	if ids == "" {
+		return nil, ErrMissingMessageId
+	}
+
+	n := len(strings.Split(ids, ","))
+	columns := map[string][]string{}
+
+	for _, key := range []string{"MsgId", "status", "status_name", "idx", "donedate", "username", "points", "from", "to", "mcc", "mnc"} {
+		values, err := batch(r.Form, key, n)
+
+		if err != nil {
+			return nil, err
+		}
+
+		columns[key] = values
+	}
+
+	reports := make([]*DeliveryReport, n)
+
+	for i := range reports {
+		report := &DeliveryReport{
+			Channel:    channel,
+			MessageId:  columns["MsgId"][i],
+			Status:     smsapi.ParseMessageStatus(columns["status"][i]),
+			StatusName: columns["status_name"][i],
+			Idx:        columns["idx"][i],
+			Username:   columns["username"][i],
+			From:       columns["from"][i],
+			To:         columns["to"][i],
+			Mcc:        columns["mcc"][i],
+			Mnc:        columns["mnc"][i],
+		}
+
+		if doneDate := columns["donedate"][i]; doneDate != "" {
+			seconds, err := strconv.ParseInt(doneDate, 10, 64)
+
+			if err != nil {
+				return nil, err
+			}
+
+			report.DoneDate = &smsapi.Timestamp{Time: time.Unix(seconds, 0)}
+		}
+
+		if points := columns["points"][i]; points != "" {
+			value, err := strconv.ParseFloat(points, 32)
+
+			if err != nil {
+				return nil, err
+			}
+
+			report.Points = smsapi.Points(value)
+		}
+
+		reports[i] = report
+	}
+
+	return reports, nil
+}
+
+type DeliveryReportFunc func(ctx context.Context, report *DeliveryReport) error
+
+// DeliveryReportHandler is an http.Handler passing delivery reports to Handle, in order.
+// Malformed requests are rejected with 400, errors returned by Handle are answered with
+// 500 so that SMSAPI repeats the request.
+type DeliveryReportHandler struct {
+	Channel Channel
+	Handle  DeliveryReportFunc
+}
+
+func NewDeliveryReportHandler(channel Channel, handle DeliveryReportFunc) *DeliveryReportHandler {
+	return &DeliveryReportHandler{Channel: channel, Handle: handle}
+}
+
+func (h *DeliveryReportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reports, err := ParseDeliveryReports(r, h.Channel)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	for _, report := range reports {
+		if err := h.Handle(r.Context(), report); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	writeOK(w)
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smsapi/smsapi-go/smsapi"
+)
+
+func TestDeliveryReportHandler(t *testing.T) {
+	var given []*DeliveryReport
+
+	handler := NewDeliveryReportHandler(ChannelSms, func(ctx context.Context, report *DeliveryReport) error {
+		given = append(given, report)
+
+		return nil
+	})
+
+	query := url.Values{
+		"MsgId":       {"1,2"},
+		"status":      {"404,405"},
+		"status_name": {"DELIVERED,UNDELIVERED"},
+		"idx":         {"a,b"},
+		"donedate":    {"1700000000,1700000060"},
+		"username":    {"user"},
+		"points":      {"0.16,0.16"},
+		"to":          {"48500600700,48500600701"},
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/dlr?"+query.Encode(), nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != ResponseOK {
+		t.Fatalf("Expected OK response, given: %d %s", w.Code, w.Body)
+	}
+
+	if len(given) != 2 {
+		t.Fatalf("Expected 2 reports, given: %d", len(given))
+	}
+
+	expected := &DeliveryReport{
+		Channel:    ChannelSms,
+		MessageId:  "2",
+		Status:     smsapi.MessageStatusUndelivered,
+		StatusName: "UNDELIVERED",
+		Idx:        "b",
+		DoneDate:   &smsapi.Timestamp{Time: time.Unix(1700000060, 0)},
+		Username:   "user",
+		Points:     0.16,
+		To:         "48500600701",
+	}
+
+	if *given[1].DoneDate != *expected.DoneDate || given[1].Status != expected.Status || given[1].Username != "user" || given[1].To != expected.To || given[1].Points != expected.Points {
+		t.Errorf("Given: %+v Expected: %+v", given[1], expected)
+	}
+}
+
+func TestDeliveryReportHandlerPostForm(t *testing.T) {
+	var given *DeliveryReport
+
+	handler := NewDeliveryReportHandler(ChannelMms, func(ctx context.Context, report *DeliveryReport) error {
+		given = report
+
+		return nil
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/dlr", strings.NewReader("MsgId=1&status=404"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK || given.Channel != ChannelMms || !given.Status.IsSuccess() {
+		t.Errorf("Unexpected report: %d %+v", w.Code, given)
+	}
+}
+
+func TestDeliveryReportHandlerErrors(t *testing.T) {
+	handler := NewDeliveryReportHandler(ChannelSms, func(ctx context.Context, report *DeliveryReport) error {
+		return errors.New("storage unavailable")
+	})
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{"status=404", http.StatusBadRequest},
+		{"MsgId=1,2&status=404,405,406", http.StatusBadRequest},
+		{"MsgId=1&donedate=yesterday", http.StatusBadRequest},
+		{"MsgId=1&status=404", http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/dlr?"+test.query, nil))
+
+		if w.Code != test.expected {
+			t.Errorf("%s expected: %d given: %d", test.query, test.expected, w.Code)
+		}
+	}
+}
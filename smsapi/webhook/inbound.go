@@ -0,0 +1,425 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smsapi/smsapi-go/smsapi"
+)
+
+const (
+	// DefaultMaxMemory is the part of a multipart MMS kept in memory while it is parsed, the rest
+	// is stored in temporary files.
+	DefaultMaxMemory = 32 << 20
+	// DefaultMaxSmsBodySize limits the body of two-way SMS callbacks.
+	DefaultMaxSmsBodySize = 64 << 10
+	// DefaultMaxMmsBodySize limits the body of two-way MMS callbacks, including attachments.
+	DefaultMaxMmsBodySize = 10 << 20
+)
+
+var (
+	ErrMissingSender = errors.New("webhook: missing sender parameter")
+	ErrNoReplySender = errors.New("webhook: reply without a ReplySender")
+)
+
+// InboundSms is a message received on a two-way number, see smsapi.Callback of sms_mo type.
+type InboundSms struct {
+	MessageId string
+	From      string
+	To        string
+	Text      string
+	Date      *smsapi.Timestamp
+	Username  string
+}
+
+// Keyword returns the first word of the message in upper case, e.g. "STOP".
+func (s *InboundSms) Keyword() string {
+	fields := strings.Fields(s.Text)
+
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.ToUpper(fields[0])
+}
+
+// InboundMms is a multimedia message received on a two-way number, see smsapi.Callback of mms_mo type.
+type InboundMms struct {
+	MessageId   string
+	From        string
+	To          string
+	Subject     string
+	Date        *smsapi.Timestamp
+	Username    string
+	Attachments []*Attachment
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+type SmsReceiver interface {
+	ReceiveSms(ctx context.Context, sms *InboundSms) error
+}
+
+type SmsReceiverFunc func(ctx context.Context, sms *InboundSms) error
+
+func (f SmsReceiverFunc) ReceiveSms(ctx context.Context, sms *InboundSms) error {
+	return f(ctx, sms)
+}
+
+type MmsReceiver interface {
+	ReceiveMms(ctx context.Context, mms *InboundMms) error
+}
+
+type MmsReceiverFunc func(ctx context.Context, mms *InboundMms) error
+
+func (f MmsReceiverFunc) ReceiveMms(ctx context.Context, mms *InboundMms) error {
+	return f(ctx, mms)
+}
+
+// Reply is a message sent back to the sender of an inbound message.
+type Reply struct {
+	Message string
+	// From is the sender name or the two-way number, the default sender name of the account when empty.
+	From string
+}
+
+// SmsResponder handles an inbound message and returns the reply, nil means no reply.
+type SmsResponder interface {
+	RespondSms(ctx context.Context, sms *InboundSms) (*Reply, error)
+}
+
+type SmsResponderFunc func(ctx context.Context, sms *InboundSms) (*Reply, error)
+
+func (f SmsResponderFunc) RespondSms(ctx context.Context, sms *InboundSms) (*Reply, error) {
+	return f(ctx, sms)
+}
+
+// ReplySender sends replies to inbound messages.
+type ReplySender interface {
+	SendReply(ctx context.Context, sms *InboundSms, reply *Reply) error
+}
+
+type ReplySenderFunc func(ctx context.Context, sms *InboundSms, reply *Reply) error
+
+func (f ReplySenderFunc) SendReply(ctx context.Context, sms *InboundSms, reply *Reply) error {
+	return f(ctx, sms, reply)
+}
+
+// replySms sends the reply with an idx derived from the inbound message id.
+type replySms struct {
+	*smsapi.Sms
+	Idx string `json:"idx,omitempty"`
+}
+
+// NewReplySender sends replies as SMS to the sender of the inbound message. SMSAPI expects
+// "OK" in response to the callback, so replies are sent with a separate API call.
+//
+// Replies are sent with idx "reply-<MsgId>" and check_idx. When a callback is redelivered,
+// e.g. because the reply was sent but the callback timed out, the repeated reply is rejected
+// by the API as a duplicate and reported as sent.
+func NewReplySender(client *smsapi.Client) ReplySender {
+	return ReplySenderFunc(func(ctx context.Context, sms *InboundSms, reply *Reply) error {
+		payload := &replySms{Sms: &smsapi.Sms{To: sms.From, Message: reply.Message, From: reply.From}}
+
+		if sms.MessageId != "" {
+			payload.Idx = "reply-" + sms.MessageId
+			payload.CheckIdx = true
+		}
+
+		err := client.LegacyPost(ctx, "/sms.do", new(smsapi.SmsResultCollection), payload)
+
+		if errors.Is(err, smsapi.ErrDuplicateIdx) {
+			return nil
+		}
+
+		return err
+	})
+}
+
+// KeywordRouter passes inbound messages to receivers registered for their keyword,
+// see InboundSms.Keyword. Messages without a matching keyword go to Default, if set.
+// Replies of responders registered with HandleReply are sent by Replies.
+//
+//	router := webhook.NewKeywordRouter()
+//	router.Replies = webhook.NewReplySender(client)
+//	router.HandleReply("INFO", func(ctx context.Context, sms *webhook.InboundSms) (*webhook.Reply, error) {
+//		return &webhook.Reply{Message: "Open 9-17"}, nil
+//	})
+type KeywordRouter struct {
+	Default SmsReceiver
+	Replies ReplySender
+
+	mu     sync.RWMutex
+	routes map[string]SmsReceiver
+}
+
+func NewKeywordRouter() *KeywordRouter {
+	return &KeywordRouter{routes: map[string]SmsReceiver{}}
+}
+
+// Handle registers the receiver for a keyword, matched case-insensitively.
+func (r *KeywordRouter) Handle(keyword string, receiver SmsReceiver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.routes == nil {
+		r.routes = map[string]SmsReceiver{}
+	}
+
+	r.routes[strings.ToUpper(keyword)] = receiver
+}
+
+func (r *KeywordRouter) HandleFunc(keyword string, receiver func(ctx context.Context, sms *InboundSms) error) {
+	r.Handle(keyword, SmsReceiverFunc(receiver))
+}
+
+// HandleReply registers the responder for a keyword, its replies are sent by Replies.
+func (r *KeywordRouter) HandleReply(keyword string, responder func(ctx context.Context, sms *InboundSms) (*Reply, error)) {
+	r.Handle(keyword, r.Respond(SmsResponderFunc(responder)))
+}
+
+// Respond adapts a responder to a receiver sending its replies by Replies, e.g. for Default.
+//
+// The reply is sent before the callback is acknowledged. When sending fails the callback is
+// answered with an error and SMSAPI delivers it again, so Replies must not repeat replies
+// already sent, as NewReplySender does with idx.
+func (r *KeywordRouter) Respond(responder SmsResponder) SmsReceiver {
+	return SmsReceiverFunc(func(ctx context.Context, sms *InboundSms) error {
+		reply, err := responder.RespondSms(ctx, sms)
+
+		if err != nil || reply == nil {
+			return err
+		}
+
+		if r.Replies == nil {
+			return ErrNoReplySender
+		}
+
+		return r.Replies.SendReply(ctx, sms, reply)
+	})
+}
+
+func (r *KeywordRouter) ReceiveSms(ctx context.Context, sms *InboundSms) error {
+	r.mu.RLock()
+	receiver, ok := r.routes[sms.Keyword()]
+	r.mu.RUnlock()
+
+	if !ok {
+		receiver = r.Default
+	}
+
+	if receiver == nil {
+		return nil
+	}
+
+	return receiver.ReceiveSms(ctx, sms)
+}
+
+// ParseInboundSms parses the two-way SMS callback parameters.
+func ParseInboundSms(r *http.Request) (*InboundSms, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	sms := &InboundSms{
+		MessageId: r.Form.Get("MsgId"),
+		From:      r.Form.Get("sms_from"),
+		To:        r.Form.Get("sms_to"),
+		Text:      r.Form.Get("sms_text"),
+		Username:  r.Form.Get("username"),
+	}
+
+	if sms.From == "" {
+		return nil, ErrMissingSender
+	}
+
+	date, err := parseUnixTimestamp(r.Form.Get("sms_date"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	sms.Date = date
+
+	return sms, nil
+}
+
+// ParseInboundMms parses the two-way MMS callback, sent as a multipart form with
+// message parameters and attached files.
+func ParseInboundMms(r *http.Request, maxMemory int64) (*InboundMms, error) {
+	if err := r.ParseMultipartForm(maxMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return nil, err
+	}
+
+	mms := &InboundMms{
+		MessageId: r.FormValue("MsgId"),
+		From:      r.FormValue("mms_from"),
+		To:        r.FormValue("mms_to"),
+		Subject:   r.FormValue("mms_subject"),
+		Username:  r.FormValue("username"),
+	}
+
+	if mms.From == "" {
+		return nil, ErrMissingSender
+	}
+
+	date, err := parseUnixTimestamp(r.FormValue("mms_date"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	mms.Date = date
+
+	if r.MultipartForm == nil {
+		return mms, nil
+	}
+
+	var fields []string
+
+	for field := range r.MultipartForm.File {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		for _, header := range r.MultipartForm.File[field] {
+			file, err := header.Open()
+
+			if err != nil {
+				return nil, err
+			}
+
+			content, err := io.ReadAll(file)
+			file.Close()
+
+			if err != nil {
+				return nil, err
+			}
+
+			mms.Attachments = append(mms.Attachments, &Attachment{
+				Filename:    header.Filename,
+				ContentType: header.Header.Get("Content-Type"),
+				Content:     content,
+			})
+		}
+	}
+
+	return mms, nil
+}
+
+func parseUnixTimestamp(value string) (*smsapi.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &smsapi.Timestamp{Time: time.Unix(seconds, 0)}, nil
+}
+
+// InboundSmsHandler is an http.Handler passing two-way SMS callbacks to the Receiver,
+// e.g. a KeywordRouter.
+type InboundSmsHandler struct {
+	Receiver SmsReceiver
+	// MaxBodySize limits the request body, DefaultMaxSmsBodySize when 0.
+	MaxBodySize int64
+}
+
+func NewInboundSmsHandler(receiver SmsReceiver) *InboundSmsHandler {
+	return &InboundSmsHandler{Receiver: receiver}
+}
+
+func (h *InboundSmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, orDefault(h.MaxBodySize, DefaultMaxSmsBodySize))
+
+	sms, err := ParseInboundSms(r)
+
+	if err != nil {
+		http.Error(w, err.Error(), parseErrorStatus(err))
+
+		return
+	}
+
+	if err := h.Receiver.ReceiveSms(r.Context(), sms); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return
+	}
+
+	writeOK(w)
+}
+
+// InboundMmsHandler is an http.Handler passing two-way MMS callbacks to the Receiver.
+type InboundMmsHandler struct {
+	Receiver MmsReceiver
+	// MaxBodySize limits the request body, DefaultMaxMmsBodySize when 0. Attachments are
+	// passed to the receiver in memory, so it also bounds their total size.
+	MaxBodySize int64
+	// MaxMemory is the part of the body kept in memory while it is parsed, DefaultMaxMemory
+	// when 0, the rest is stored in temporary files.
+	MaxMemory int64
+}
+
+func NewInboundMmsHandler(receiver MmsReceiver) *InboundMmsHandler {
+	return &InboundMmsHandler{Receiver: receiver}
+}
+
+func (h *InboundMmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, orDefault(h.MaxBodySize, DefaultMaxMmsBodySize))
+
+	mms, err := ParseInboundMms(r, orDefault(h.MaxMemory, DefaultMaxMemory))
+
+	if r.MultipartForm != nil {
+		defer r.MultipartForm.RemoveAll()
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), parseErrorStatus(err))
+
+		return
+	}
+
+	if err := h.Receiver.ReceiveMms(r.Context(), mms); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return
+	}
+
+	writeOK(w)
+}
+
+// parseErrorStatus reports bodies over the size limit as 413 Request Entity Too Large.
+func parseErrorStatus(err error) int {
+	var maxBytesError *http.MaxBytesError
+
+	if errors.As(err, &maxBytesError) {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return http.StatusBadRequest
+}
+
+func orDefault(value, defaultValue int64) int64 {
+	if value == 0 {
+		return defaultValue
+	}
+
+	return value
+}
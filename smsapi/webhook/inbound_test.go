@@ -0,0 +1,266 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smsapi/smsapi-go/smsapi"
+)
+
+func postForm(values url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/mo", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return r
+}
+
+func TestInboundSmsHandler(t *testing.T) {
+	var given *InboundSms
+
+	handler := NewInboundSmsHandler(SmsReceiverFunc(func(ctx context.Context, sms *InboundSms) error {
+		given = sms
+
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, postForm(url.Values{
+		"MsgId":    {"1"},
+		"sms_from": {"48500600700"},
+		"sms_to":   {"48500600800"},
+		"sms_text": {"Stop please"},
+		"sms_date": {"1700000000"},
+		"username": {"user"},
+	}))
+
+	if w.Code != http.StatusOK || w.Body.String() != ResponseOK {
+		t.Fatalf("Expected OK response, given: %d %s", w.Code, w.Body)
+	}
+
+	if given.From != "48500600700" || given.Text != "Stop please" || !given.Date.Time.Equal(time.Unix(1700000000, 0)) || given.Keyword() != "STOP" {
+		t.Errorf("Unexpected message: %+v", given)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, postForm(url.Values{"sms_text": {"test"}}))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected missing sender to be rejected, given: %d", w.Code)
+	}
+}
+
+func TestKeywordRouter(t *testing.T) {
+	var routed []string
+
+	router := NewKeywordRouter()
+
+	router.HandleFunc("stop", func(ctx context.Context, sms *InboundSms) error {
+		routed = append(routed, "stop:"+sms.Text)
+
+		return nil
+	})
+
+	router.Default = SmsReceiverFunc(func(ctx context.Context, sms *InboundSms) error {
+		routed = append(routed, "default:"+sms.Text)
+
+		return nil
+	})
+
+	for _, text := range []string{"STOP", "  stop now", "hello", ""} {
+		router.ReceiveSms(ctx(), &InboundSms{Text: text})
+	}
+
+	expected := "stop:STOP|stop:  stop now|default:hello|default:"
+
+	if strings.Join(routed, "|") != expected {
+		t.Errorf("Expected: %s given: %s", expected, strings.Join(routed, "|"))
+	}
+}
+
+func TestInboundMmsHandler(t *testing.T) {
+	var given *InboundMms
+
+	handler := NewInboundMmsHandler(MmsReceiverFunc(func(ctx context.Context, mms *InboundMms) error {
+		given = mms
+
+		return nil
+	}))
+
+	body := new(bytes.Buffer)
+	form := multipart.NewWriter(body)
+
+	form.WriteField("MsgId", "1")
+	form.WriteField("mms_from", "48500600700")
+	form.WriteField("mms_to", "48500600800")
+	form.WriteField("mms_subject", "Photo")
+	form.WriteField("mms_date", "1700000000")
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file1"; filename="photo.jpg"`)
+	header.Set("Content-Type", "image/jpeg")
+
+	part, _ := form.CreatePart(header)
+	part.Write([]byte("jpeg"))
+
+	form.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/mo", body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected OK response, given: %d %s", w.Code, w.Body)
+	}
+
+	if given.Subject != "Photo" || len(given.Attachments) != 1 {
+		t.Fatalf("Unexpected message: %+v", given)
+	}
+
+	attachment := given.Attachments[0]
+
+	if attachment.Filename != "photo.jpg" || attachment.ContentType != "image/jpeg" || string(attachment.Content) != "jpeg" {
+		t.Errorf("Unexpected attachment: %+v", attachment)
+	}
+}
+
+func ctx() context.Context {
+	return context.Background()
+}
+
+func TestKeywordRouterReply(t *testing.T) {
+	var replies []string
+
+	router := NewKeywordRouter()
+
+	router.Replies = ReplySenderFunc(func(ctx context.Context, sms *InboundSms, reply *Reply) error {
+		replies = append(replies, sms.From+":"+reply.Message+":"+reply.From)
+
+		return nil
+	})
+
+	router.HandleReply("info", func(ctx context.Context, sms *InboundSms) (*Reply, error) {
+		return &Reply{Message: "Open 9-17", From: "Shop"}, nil
+	})
+
+	router.Default = router.Respond(SmsResponderFunc(func(ctx context.Context, sms *InboundSms) (*Reply, error) {
+		return nil, nil
+	}))
+
+	for _, text := range []string{"INFO", "hello"} {
+		if err := router.ReceiveSms(ctx(), &InboundSms{From: "48500600700", Text: text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := "48500600700:Open 9-17:Shop"
+
+	if strings.Join(replies, "|") != expected {
+		t.Errorf("Expected: %s given: %s", expected, strings.Join(replies, "|"))
+	}
+
+	router.Replies = nil
+
+	if err := router.ReceiveSms(ctx(), &InboundSms{Text: "info"}); !errors.Is(err, ErrNoReplySender) {
+		t.Errorf("Expected ErrNoReplySender, given: %v", err)
+	}
+}
+
+func TestInboundHandlersBodyLimit(t *testing.T) {
+	received := false
+
+	smsHandler := &InboundSmsHandler{
+		Receiver: SmsReceiverFunc(func(ctx context.Context, sms *InboundSms) error {
+			received = true
+
+			return nil
+		}),
+		MaxBodySize: 64,
+	}
+
+	w := httptest.NewRecorder()
+	smsHandler.ServeHTTP(w, postForm(url.Values{"sms_from": {"48500600700"}, "sms_text": {strings.Repeat("a", 100)}}))
+
+	if w.Code != http.StatusRequestEntityTooLarge || received {
+		t.Errorf("Expected 413, given: %d", w.Code)
+	}
+
+	mmsHandler := &InboundMmsHandler{
+		Receiver: MmsReceiverFunc(func(ctx context.Context, mms *InboundMms) error {
+			received = true
+
+			return nil
+		}),
+		MaxBodySize: 64,
+	}
+
+	body := new(bytes.Buffer)
+	form := multipart.NewWriter(body)
+
+	form.WriteField("mms_from", "48500600700")
+	part, _ := form.CreateFormFile("file1", "photo.jpg")
+	part.Write(bytes.Repeat([]byte{0xff}, 100))
+	form.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/mo", body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+
+	w = httptest.NewRecorder()
+	mmsHandler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusRequestEntityTooLarge || received {
+		t.Errorf("Expected 413, given: %d", w.Code)
+	}
+}
+
+func TestReplySenderIdx(t *testing.T) {
+	var requests []map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		requests = append(requests, body)
+
+		if len(requests) > 1 {
+			fmt.Fprint(w, `{"error":53,"message":"Duplicated idx"}`)
+
+			return
+		}
+
+		fmt.Fprint(w, `{"count":1,"list":[{"id":"1","number":"48500600700"}]}`)
+	}))
+	defer server.Close()
+
+	client, err := smsapi.New(smsapi.WithToken("token"), smsapi.WithBaseURL(server.URL+"/"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender := NewReplySender(client)
+	sms := &InboundSms{MessageId: "42", From: "48500600700"}
+
+	for i := 0; i < 2; i++ {
+		if err := sender.SendReply(ctx(), sms, &Reply{Message: "Open 9-17"}); err != nil {
+			t.Fatalf("Expected repeated reply to be reported as sent, given: %v", err)
+		}
+	}
+
+	if len(requests) != 2 || requests[0]["idx"] != "reply-42" || requests[0]["check_idx"] != true || requests[0]["to"] != "48500600700" {
+		t.Errorf("Unexpected requests: %v", requests)
+	}
+}
@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func countingHandler(calls *int, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+
+		if status != http.StatusOK {
+			http.Error(w, "error", status)
+
+			return
+		}
+
+		writeOK(w)
+	})
+}
+
+func serve(handler http.Handler, target, remoteAddr string, forwardedFor ...string) int {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	r.RemoteAddr = remoteAddr
+
+	for _, value := range forwardedFor {
+		r.Header.Add("X-Forwarded-For", value)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	return w.Code
+}
+
+func TestGuardAllowedNetworks(t *testing.T) {
+	calls := 0
+
+	guard := &Guard{
+		AllowedNetworks: MustParsePrefixes("192.0.2.0/24"),
+		TrustedProxies:  MustParsePrefixes("10.0.0.0/8"),
+	}
+
+	handler := guard.Wrap(countingHandler(&calls, http.StatusOK))
+
+	tests := []struct {
+		remoteAddr   string
+		forwardedFor []string
+		expected     int
+	}{
+		{"192.0.2.10:5000", nil, http.StatusOK},
+		{"198.51.100.1:5000", nil, http.StatusForbidden},
+		{"10.0.0.1:5000", []string{"192.0.2.10"}, http.StatusOK},
+		{"10.0.0.1:5000", []string{"192.0.2.10, 10.0.0.2"}, http.StatusOK},
+		{"10.0.0.1:5000", []string{"192.0.2.10", "198.51.100.1"}, http.StatusForbidden},
+		{"198.51.100.1:5000", []string{"192.0.2.10"}, http.StatusForbidden},
+		{"10.0.0.1:5000", []string{"invalid"}, http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		if given := serve(handler, "/dlr", test.remoteAddr, test.forwardedFor...); given != test.expected {
+			t.Errorf("%s %v expected: %d given: %d", test.remoteAddr, test.forwardedFor, test.expected, given)
+		}
+	}
+}
+
+func TestGuardSmsapiNetworks(t *testing.T) {
+	defer func(networks []netip.Prefix) { SmsapiNetworks = networks }(SmsapiNetworks)
+
+	SmsapiNetworks = MustParsePrefixes("192.0.2.0/24")
+
+	calls := 0
+	handler := (&Guard{}).Wrap(countingHandler(&calls, http.StatusOK))
+
+	if serve(handler, "/dlr", "198.51.100.1:5000") != http.StatusForbidden {
+		t.Error("Expected SmsapiNetworks to be used by default")
+	}
+}
+
+func TestGuardWithoutNetworks(t *testing.T) {
+	calls := 0
+
+	if serve((&Guard{}).Wrap(countingHandler(&calls, http.StatusOK)), "/dlr", "192.0.2.10:5000") != http.StatusForbidden {
+		t.Error("Expected callbacks to be rejected without networks")
+	}
+
+	if serve((&Guard{AllowAllNetworks: true}).Wrap(countingHandler(&calls, http.StatusOK)), "/dlr", "192.0.2.10:5000") != http.StatusOK {
+		t.Error("Expected AllowAllNetworks to disable the check")
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected handler to be called once, given: %d", calls)
+	}
+}
+
+func TestGuardToken(t *testing.T) {
+	calls := 0
+	handler := (&Guard{AllowAllNetworks: true, Token: "secret"}).Wrap(countingHandler(&calls, http.StatusOK))
+
+	if serve(handler, "/dlr?token=secret", "192.0.2.10:5000") != http.StatusOK {
+		t.Error("Expected valid token to be accepted")
+	}
+
+	if serve(handler, "/dlr?token=guess", "192.0.2.10:5000") != http.StatusForbidden || serve(handler, "/dlr", "192.0.2.10:5000") != http.StatusForbidden {
+		t.Error("Expected invalid token to be rejected")
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected handler to be called once, given: %d", calls)
+	}
+}
+
+func TestGuardReplay(t *testing.T) {
+	calls, failing := 0, 0
+
+	guard := &Guard{AllowAllNetworks: true, Replay: NewReplayCache(10, time.Hour)}
+
+	handler := guard.Wrap(countingHandler(&calls, http.StatusOK))
+
+	for _, target := range []string{"/dlr?MsgId=1&status=403", "/dlr?MsgId=1&status=403", "/dlr?MsgId=1&status=404"} {
+		if serve(handler, target, "192.0.2.10:5000") != http.StatusOK {
+			t.Errorf("Expected OK response for %s", target)
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected repeated callback to be skipped, given %d calls", calls)
+	}
+
+	failingHandler := guard.Wrap(countingHandler(&failing, http.StatusInternalServerError))
+
+	serve(failingHandler, "/dlr?MsgId=2&status=404", "192.0.2.10:5000")
+	serve(failingHandler, "/dlr?MsgId=2&status=404", "192.0.2.10:5000")
+
+	if failing != 2 {
+		t.Errorf("Expected failed callback to be handled again, given %d calls", failing)
+	}
+}
+
+func TestReplayCache(t *testing.T) {
+	cache := NewReplayCache(2, 0)
+
+	cache.Add("a")
+	cache.Add("b")
+	cache.Add("c")
+
+	if cache.Seen("a") || !cache.Seen("b") || !cache.Seen("c") {
+		t.Error("Expected the oldest key to be evicted")
+	}
+
+	expiring := NewReplayCache(2, time.Millisecond)
+	expiring.Add("a")
+
+	time.Sleep(2 * time.Millisecond)
+
+	if expiring.Seen("a") {
+		t.Error("Expected key to expire")
+	}
+}
+
+func TestGuardConcurrentReplay(t *testing.T) {
+	var calls atomic.Int32
+
+	started, release := make(chan struct{}), make(chan struct{})
+
+	guard := &Guard{AllowAllNetworks: true, Replay: NewReplayCache(10, time.Hour)}
+
+	handler := guard.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		close(started)
+		<-release
+
+		writeOK(w)
+	}))
+
+	done := make(chan int)
+
+	go func() { done <- serve(handler, "/dlr?MsgId=1&status=404", "192.0.2.10:5000") }()
+
+	<-started
+
+	if serve(handler, "/dlr?MsgId=1&status=404", "192.0.2.10:5000") != http.StatusOK {
+		t.Error("Expected OK response for a callback being handled")
+	}
+
+	close(release)
+	<-done
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected callback to be handled once, given %d calls", calls.Load())
+	}
+}
+
+func TestReplayCacheReserve(t *testing.T) {
+	cache := NewReplayCache(2, 0)
+
+	if !cache.Reserve("a") || cache.Reserve("a") {
+		t.Error("Expected the key to be reserved once")
+	}
+
+	cache.Release("a")
+
+	if !cache.Reserve("a") {
+		t.Error("Expected released key to be reserved again")
+	}
+
+	cache.Add("a")
+
+	if cache.Reserve("a") || !cache.Seen("a") {
+		t.Error("Expected added key not to be reserved")
+	}
+}
@@ -0,0 +1,63 @@
+// Package webhook receives callbacks SMSAPI sends to URLs registered with smsapi.CallbacksApi.
+//
+//	http.Handle("/smsapi/dlr", webhook.NewDeliveryReportHandler(webhook.ChannelSms,
+//		func(ctx context.Context, report *webhook.DeliveryReport) error {
+//			return store.UpdateStatus(ctx, report.MessageId, report.Status)
+//		}))
+//
+// Handlers reply with the "OK" body SMSAPI expects once all callbacks of a request are
+// handled. Otherwise SMSAPI repeats the request, so handler funcs should be idempotent.
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ResponseOK is the body SMSAPI expects in reply to a handled callback.
+const ResponseOK = "OK"
+
+type Channel string
+
+const (
+	ChannelSms = Channel("sms")
+	ChannelMms = Channel("mms")
+	ChannelVms = Channel("vms")
+)
+
+var ErrBatchMismatch = errors.New("webhook: batched parameters have different lengths")
+
+func writeOK(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(ResponseOK))
+}
+
+// batch splits comma separated values of batched callbacks. Parameters with a single value
+// are repeated for all n callbacks.
+func batch(form url.Values, key string, n int) ([]string, error) {
+	value := form.Get(key)
+	result := make([]string, n)
+
+	if value == "" {
+		return result, nil
+	}
+
+	values := strings.Split(value, ",")
+
+	switch len(values) {
+	case n:
+		return values, nil
+	case 1:
+		for i := range result {
+			result[i] = value
+		}
+
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s has %d values, expected %d", ErrBatchMismatch, key, len(values), n)
+}
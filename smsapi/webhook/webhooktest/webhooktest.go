@@ -0,0 +1,515 @@
+// Package webhooktest simulates callbacks SMSAPI sends, for testing webhook consumers offline.
+//
+//	harness := webhooktest.NewHarness(callback, handler)
+//	reports := webhooktest.SmsReports(response)
+//	err := harness.Run(ctx, webhooktest.DelayedDelivery(time.Minute), reports)
+//
+// Requests carry the same parameters the webhook package parses. They are sent to an
+// http.Handler directly or to a URL, such as a local server. A harness of a registered
+// callback only sends the traffic of its type: delivery reports of its channel for sms, mms
+// and vms callbacks, and inbound messages for sms_mo and mms_mo callbacks.
+//
+// MFA codes are sent as SMS, so their callbacks are SMS delivery reports, see MfaReports.
+package webhooktest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smsapi/smsapi-go/smsapi"
+	"github.com/smsapi/smsapi-go/smsapi/webhook"
+)
+
+var (
+	ErrNotAcknowledged = errors.New("webhooktest: callback not acknowledged")
+	ErrCallbackType    = errors.New("webhooktest: traffic not sent to the callback type")
+	ErrCommaInValue    = errors.New("webhooktest: delivery report value contains a comma")
+)
+
+// DefaultRemoteAddr is the source address of callbacks passed to a Handler when SmsapiNetworks
+// is empty, taken from the documentation range 192.0.2.0/24.
+const DefaultRemoteAddr = "192.0.2.1:1234"
+
+const (
+	CallbackTypeSmsMo = "sms_mo"
+	CallbackTypeMmsMo = "mms_mo"
+)
+
+// DeliveryReportRequest builds a delivery report callback request. Several reports are sent
+// in a single request as comma separated values, as SMSAPI batches them. Values containing
+// a comma, e.g. an idx, cannot be told apart from batched ones and are rejected with
+// ErrCommaInValue.
+func DeliveryReportRequest(callbackUrl string, reports ...*webhook.DeliveryReport) (*http.Request, error) {
+	columns := map[string][]string{}
+
+	for _, report := range reports {
+		for _, value := range []string{report.MessageId, report.StatusName, report.Idx, report.Username, report.From, report.To, report.Mcc, report.Mnc} {
+			if strings.Contains(value, ",") {
+				return nil, fmt.Errorf("%w: %q of message %s", ErrCommaInValue, value, report.MessageId)
+			}
+		}
+
+		var doneDate string
+
+		if report.DoneDate != nil {
+			doneDate = strconv.FormatInt(report.DoneDate.Unix(), 10)
+		}
+
+		status := strconv.Itoa(report.Status.Code())
+
+		if !report.Status.IsKnown() {
+			status = string(report.Status)
+		}
+
+		statusName := report.StatusName
+
+		if statusName == "" {
+			statusName = string(report.Status)
+		}
+
+		columns["MsgId"] = append(columns["MsgId"], report.MessageId)
+		columns["status"] = append(columns["status"], status)
+		columns["status_name"] = append(columns["status_name"], statusName)
+		columns["idx"] = append(columns["idx"], report.Idx)
+		columns["donedate"] = append(columns["donedate"], doneDate)
+		columns["username"] = append(columns["username"], report.Username)
+		columns["points"] = append(columns["points"], strconv.FormatFloat(float64(report.Points), 'f', -1, 32))
+		columns["from"] = append(columns["from"], report.From)
+		columns["to"] = append(columns["to"], report.To)
+		columns["mcc"] = append(columns["mcc"], report.Mcc)
+		columns["mnc"] = append(columns["mnc"], report.Mnc)
+	}
+
+	u, err := url.Parse(callbackUrl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+
+	for key, values := range columns {
+		if strings.Join(values, "") != "" {
+			query.Set(key, strings.Join(values, ","))
+		}
+	}
+
+	u.RawQuery = query.Encode()
+
+	return http.NewRequest(http.MethodGet, u.String(), nil)
+}
+
+// InboundSmsRequest builds a two-way SMS callback request, sent as a form.
+func InboundSmsRequest(callbackUrl string, sms *webhook.InboundSms) (*http.Request, error) {
+	form := url.Values{
+		"MsgId":    {sms.MessageId},
+		"sms_from": {sms.From},
+		"sms_to":   {sms.To},
+		"sms_text": {sms.Text},
+		"username": {sms.Username},
+	}
+
+	if sms.Date != nil {
+		form.Set("sms_date", strconv.FormatInt(sms.Date.Unix(), 10))
+	}
+
+	r, err := http.NewRequest(http.MethodPost, callbackUrl, strings.NewReader(form.Encode()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return r, nil
+}
+
+// InboundMmsRequest builds a two-way MMS callback request, sent as a multipart form with
+// attachments as files.
+func InboundMmsRequest(callbackUrl string, mms *webhook.InboundMms) (*http.Request, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	fields := [][2]string{
+		{"MsgId", mms.MessageId},
+		{"mms_from", mms.From},
+		{"mms_to", mms.To},
+		{"mms_subject", mms.Subject},
+		{"username", mms.Username},
+	}
+
+	if mms.Date != nil {
+		fields = append(fields, [2]string{"mms_date", strconv.FormatInt(mms.Date.Unix(), 10)})
+	}
+
+	for _, field := range fields {
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, attachment := range mms.Attachments {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file%d"; filename=%q`, i, attachment.Filename))
+		header.Set("Content-Type", attachment.ContentType)
+
+		part, err := writer.CreatePart(header)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := part.Write(attachment.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	r, err := http.NewRequest(http.MethodPost, callbackUrl, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return r, nil
+}
+
+// SmsReports returns delivery reports of sent messages, with statuses from the responses.
+func SmsReports(responses ...*smsapi.SmsResponse) []*webhook.DeliveryReport {
+	reports := make([]*webhook.DeliveryReport, len(responses))
+
+	for i, r := range responses {
+		reports[i] = report(webhook.ChannelSms, r.Id, r.Number, r.Idx, r.Status, r.Points)
+	}
+
+	return reports
+}
+
+// MmsReports returns delivery reports of sent messages, see SmsReports.
+func MmsReports(responses ...*smsapi.MmsResponse) []*webhook.DeliveryReport {
+	reports := make([]*webhook.DeliveryReport, len(responses))
+
+	for i, r := range responses {
+		reports[i] = report(webhook.ChannelMms, r.Id, r.Number, r.Idx, r.Status, r.Points)
+	}
+
+	return reports
+}
+
+// VmsReports returns delivery reports of sent messages, see SmsReports.
+func VmsReports(responses ...*smsapi.VmsResponse) []*webhook.DeliveryReport {
+	reports := make([]*webhook.DeliveryReport, len(responses))
+
+	for i, r := range responses {
+		reports[i] = report(webhook.ChannelVms, r.Id, r.Number, r.Idx, r.Status, r.Points)
+	}
+
+	return reports
+}
+
+// MfaReports returns delivery reports of sent MFA codes. The SMS carrying a code is reported
+// like other SMS, with the code id as the message id and the code sender as From.
+func MfaReports(codes ...*smsapi.MfaCode) []*webhook.DeliveryReport {
+	reports := make([]*webhook.DeliveryReport, len(codes))
+
+	for i, code := range codes {
+		reports[i] = report(webhook.ChannelSms, code.Id, code.PhoneNumber, "", smsapi.MessageStatusSent, 0)
+		reports[i].From = code.From
+	}
+
+	return reports
+}
+
+func report(channel webhook.Channel, id, number, idx string, status smsapi.MessageStatus, points smsapi.Points) *webhook.DeliveryReport {
+	return &webhook.DeliveryReport{
+		Channel:   channel,
+		MessageId: id,
+		Status:    status,
+		Idx:       idx,
+		Points:    points,
+		To:        number,
+	}
+}
+
+// Step is a delivery report sent After the previous step of a Scenario.
+type Step struct {
+	After  time.Duration
+	Status smsapi.MessageStatus
+}
+
+type Scenario []Step
+
+// Delivered reports messages sent and then delivered.
+func Delivered() Scenario {
+	return Scenario{
+		{Status: smsapi.MessageStatusSent},
+		{Status: smsapi.MessageStatusDelivered},
+	}
+}
+
+// DelayedDelivery reports messages queued, e.g. for a recipient out of range, and
+// delivered after the delay.
+func DelayedDelivery(delay time.Duration) Scenario {
+	return Scenario{
+		{Status: smsapi.MessageStatusQueue},
+		{Status: smsapi.MessageStatusSent},
+		{After: delay, Status: smsapi.MessageStatusDelivered},
+	}
+}
+
+// Failure reports messages sent and ending with the failed status, e.g. UNDELIVERED or REJECTED.
+func Failure(status smsapi.MessageStatus) Scenario {
+	return Scenario{
+		{Status: smsapi.MessageStatusSent},
+		{Status: status},
+	}
+}
+
+// Expiry reports messages sent and expired after their validity.
+func Expiry(validity time.Duration) Scenario {
+	return Scenario{
+		{Status: smsapi.MessageStatusSent},
+		{After: validity, Status: smsapi.MessageStatusExpired},
+	}
+}
+
+// Harness sends simulated callbacks to the Handler, or to the URL when Handler is nil.
+type Harness struct {
+	// URL is the callback url, with its query parameters such as the token of smsapi.SignCallbackUrl.
+	URL     string
+	Handler http.Handler
+	// Type is the callback type, e.g. sms or sms_mo, traffic of other types is rejected with
+	// ErrCallbackType. Any traffic is sent when empty.
+	Type string
+	// ReceiverNumber is the recipient of inbound messages without To.
+	ReceiverNumber string
+	// RemoteAddr is the source address of requests passed to the Handler, by default the first
+	// address of webhook.SmsapiNetworks, or DefaultRemoteAddr when it is empty, so that
+	// handlers wrapped in webhook.Guard accept them.
+	RemoteAddr string
+	// Client sends requests to the URL, http.DefaultClient when nil.
+	Client *http.Client
+	// BatchSize is the maximum number of delivery reports sent in a single request, 1 when 0.
+	BatchSize int
+	// Wait waits between scenario steps, by default it sleeps. Tests may replace it to run
+	// scenarios without delay or with a fake clock.
+	Wait func(ctx context.Context, d time.Duration) error
+	// Now returns the done date of delivery reports, time.Now when nil.
+	Now func() time.Time
+}
+
+// NewHarness returns a harness sending the traffic of the callback type to its url.
+func NewHarness(callback *smsapi.Callback, handler http.Handler) *Harness {
+	return &Harness{
+		URL:            callback.Url,
+		Handler:        handler,
+		Type:           callback.Type,
+		ReceiverNumber: callback.ReceiverNumber,
+	}
+}
+
+// DeliverReports sends delivery reports in requests of at most BatchSize reports.
+func (h *Harness) DeliverReports(ctx context.Context, reports ...*webhook.DeliveryReport) error {
+	for _, report := range reports {
+		if err := h.checkType(string(report.Channel)); err != nil {
+			return err
+		}
+	}
+
+	batchSize := max(h.BatchSize, 1)
+
+	for start := 0; start < len(reports); start += batchSize {
+		r, err := DeliveryReportRequest(h.URL, reports[start:min(start+batchSize, len(reports))]...)
+
+		if err != nil {
+			return err
+		}
+
+		if err := h.Do(ctx, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *Harness) ReceiveSms(ctx context.Context, sms *webhook.InboundSms) error {
+	if err := h.checkType(CallbackTypeSmsMo); err != nil {
+		return err
+	}
+
+	if sms.To == "" && h.ReceiverNumber != "" {
+		received := *sms
+		received.To = h.ReceiverNumber
+		sms = &received
+	}
+
+	r, err := InboundSmsRequest(h.URL, sms)
+
+	if err != nil {
+		return err
+	}
+
+	return h.Do(ctx, r)
+}
+
+func (h *Harness) ReceiveMms(ctx context.Context, mms *webhook.InboundMms) error {
+	if err := h.checkType(CallbackTypeMmsMo); err != nil {
+		return err
+	}
+
+	if mms.To == "" && h.ReceiverNumber != "" {
+		received := *mms
+		received.To = h.ReceiverNumber
+		mms = &received
+	}
+
+	r, err := InboundMmsRequest(h.URL, mms)
+
+	if err != nil {
+		return err
+	}
+
+	return h.Do(ctx, r)
+}
+
+// Run sends delivery reports of every scenario step, with the step status and done date.
+// It stops at the first callback not acknowledged.
+func (h *Harness) Run(ctx context.Context, scenario Scenario, reports []*webhook.DeliveryReport) error {
+	for _, report := range reports {
+		if err := h.checkType(string(report.Channel)); err != nil {
+			return err
+		}
+	}
+
+	wait := h.Wait
+
+	if wait == nil {
+		wait = sleep
+	}
+
+	now := h.Now
+
+	if now == nil {
+		now = time.Now
+	}
+
+	for _, step := range scenario {
+		if err := wait(ctx, step.After); err != nil {
+			return err
+		}
+
+		doneDate := &smsapi.Timestamp{Time: now()}
+		stepReports := make([]*webhook.DeliveryReport, len(reports))
+
+		for i, report := range reports {
+			stepReport := *report
+			stepReport.Status = step.Status
+			stepReport.StatusName = string(step.Status)
+			stepReport.DoneDate = doneDate
+			stepReports[i] = &stepReport
+		}
+
+		if err := h.DeliverReports(ctx, stepReports...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Do sends the callback request and checks it was acknowledged with webhook.ResponseOK.
+func (h *Harness) Do(ctx context.Context, r *http.Request) error {
+	r = r.WithContext(ctx)
+
+	var status int
+	var body []byte
+
+	if h.Handler != nil {
+		r.RemoteAddr = h.remoteAddr()
+
+		w := httptest.NewRecorder()
+		h.Handler.ServeHTTP(w, r)
+
+		status, body = w.Code, w.Body.Bytes()
+	} else {
+		client := h.Client
+
+		if client == nil {
+			client = http.DefaultClient
+		}
+
+		response, err := client.Do(r)
+
+		if err != nil {
+			return err
+		}
+
+		defer response.Body.Close()
+
+		status = response.StatusCode
+
+		if body, err = io.ReadAll(response.Body); err != nil {
+			return err
+		}
+	}
+
+	if status != http.StatusOK || strings.TrimSpace(string(body)) != webhook.ResponseOK {
+		return fmt.Errorf("%w: %d %s", ErrNotAcknowledged, status, body)
+	}
+
+	return nil
+}
+
+func (h *Harness) remoteAddr() string {
+	if h.RemoteAddr != "" {
+		return h.RemoteAddr
+	}
+
+	if len(webhook.SmsapiNetworks) > 0 {
+		return netip.AddrPortFrom(webhook.SmsapiNetworks[0].Addr(), 1234).String()
+	}
+
+	return DefaultRemoteAddr
+}
+
+func (h *Harness) checkType(callbackType string) error {
+	if h.Type == "" || h.Type == callbackType {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s traffic to a %s callback", ErrCallbackType, callbackType, h.Type)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,252 @@
+package webhooktest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/smsapi/smsapi-go/smsapi"
+	"github.com/smsapi/smsapi-go/smsapi/webhook"
+)
+
+func noWait(ctx context.Context, d time.Duration) error {
+	return nil
+}
+
+func TestHarnessRun(t *testing.T) {
+	var given []*webhook.DeliveryReport
+	var requests int
+
+	handler := webhook.NewDeliveryReportHandler(webhook.ChannelSms, func(ctx context.Context, report *webhook.DeliveryReport) error {
+		given = append(given, report)
+
+		return nil
+	})
+
+	guard := &webhook.Guard{AllowedNetworks: webhook.MustParsePrefixes("192.0.2.0/24"), Token: "secret"}
+
+	callback := &smsapi.Callback{Url: "https://example.com/dlr?token=secret", Type: "sms"}
+
+	harness := NewHarness(callback, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		guard.Wrap(handler).ServeHTTP(w, r)
+	}))
+	harness.BatchSize = 2
+
+	var waited []time.Duration
+
+	harness.Wait = func(ctx context.Context, d time.Duration) error {
+		waited = append(waited, d)
+
+		return nil
+	}
+
+	reports := SmsReports(
+		&smsapi.SmsResponse{Id: "1", Number: "48500600700", Idx: "a", Points: 0.16},
+		&smsapi.SmsResponse{Id: "2", Number: "48500600701", Points: 0.16},
+		&smsapi.SmsResponse{Id: "3", Number: "48500600702", Idx: "c", Points: 0.16},
+	)
+
+	if err := harness.Run(context.Background(), DelayedDelivery(time.Hour), reports); err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 6 {
+		t.Errorf("Expected 2 requests per step, given: %d", requests)
+	}
+
+	if len(waited) != 3 || waited[2] != time.Hour {
+		t.Errorf("Unexpected waits: %v", waited)
+	}
+
+	if len(given) != 9 {
+		t.Fatalf("Expected 9 reports, given: %d", len(given))
+	}
+
+	last := given[7]
+
+	if last.MessageId != "2" || last.Status != smsapi.MessageStatusDelivered || last.To != "48500600701" || last.Idx != "" || last.DoneDate == nil || last.Points != 0.16 {
+		t.Errorf("Unexpected report: %+v", last)
+	}
+
+	if given[6].Idx != "a" || given[0].Status != smsapi.MessageStatusQueue {
+		t.Errorf("Unexpected reports: %+v %+v", given[6], given[0])
+	}
+}
+
+func TestHarnessNotAcknowledged(t *testing.T) {
+	handler := webhook.NewDeliveryReportHandler(webhook.ChannelSms, func(ctx context.Context, report *webhook.DeliveryReport) error {
+		return errors.New("failed")
+	})
+
+	harness := &Harness{URL: "/dlr", Handler: handler, Wait: noWait}
+
+	reports := SmsReports(&smsapi.SmsResponse{Id: "1"})
+
+	err := harness.Run(context.Background(), Failure(smsapi.MessageStatusUndelivered), reports)
+
+	if !errors.Is(err, ErrNotAcknowledged) {
+		t.Errorf("Expected ErrNotAcknowledged, given: %v", err)
+	}
+}
+
+func TestHarnessUrl(t *testing.T) {
+	var given *webhook.InboundSms
+
+	server := httptest.NewServer(webhook.NewInboundSmsHandler(webhook.SmsReceiverFunc(func(ctx context.Context, sms *webhook.InboundSms) error {
+		given = sms
+
+		return nil
+	})))
+	defer server.Close()
+
+	harness := &Harness{URL: server.URL + "/mo"}
+
+	sms := &webhook.InboundSms{
+		MessageId: "1",
+		From:      "48500600700",
+		To:        "48500600701",
+		Text:      "STOP zażółć",
+		Date:      &smsapi.Timestamp{Time: time.Unix(1700000000, 0)},
+		Username:  "user",
+	}
+
+	if err := harness.ReceiveSms(context.Background(), sms); err != nil {
+		t.Fatal(err)
+	}
+
+	if given == nil || given.MessageId != "1" || given.To != sms.To || given.Text != sms.Text || given.Username != "user" || !given.Date.Time.Equal(sms.Date.Time) {
+		t.Errorf("Unexpected sms: %+v", given)
+	}
+}
+
+func TestHarnessReceiveMms(t *testing.T) {
+	var given *webhook.InboundMms
+
+	harness := &Harness{URL: "/mo", Handler: webhook.NewInboundMmsHandler(webhook.MmsReceiverFunc(func(ctx context.Context, mms *webhook.InboundMms) error {
+		given = mms
+
+		return nil
+	}))}
+
+	mms := &webhook.InboundMms{
+		From:    "48500600700",
+		Subject: "Photo",
+		Attachments: []*webhook.Attachment{
+			{Filename: "a.jpg", ContentType: "image/jpeg", Content: []byte{0xff, 0xd8}},
+			{Filename: "b.txt", ContentType: "text/plain", Content: []byte("text")},
+		},
+	}
+
+	if err := harness.ReceiveMms(context.Background(), mms); err != nil {
+		t.Fatal(err)
+	}
+
+	if given == nil || given.Subject != "Photo" || len(given.Attachments) != 2 {
+		t.Fatalf("Unexpected mms: %+v", given)
+	}
+
+	for i, attachment := range given.Attachments {
+		expected := mms.Attachments[i]
+
+		if attachment.Filename != expected.Filename || attachment.ContentType != expected.ContentType || !bytes.Equal(attachment.Content, expected.Content) {
+			t.Errorf("Unexpected attachment %d: %+v", i, attachment)
+		}
+	}
+}
+
+func TestHarnessCallbackType(t *testing.T) {
+	var given *webhook.InboundSms
+
+	callback := &smsapi.Callback{Url: "/mo", Type: "sms_mo", ReceiverNumber: "48500000000"}
+
+	harness := NewHarness(callback, webhook.NewInboundSmsHandler(webhook.SmsReceiverFunc(func(ctx context.Context, sms *webhook.InboundSms) error {
+		given = sms
+
+		return nil
+	})))
+
+	if err := harness.ReceiveSms(context.Background(), &webhook.InboundSms{From: "48500600700", Text: "STOP"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if given == nil || given.To != "48500000000" {
+		t.Errorf("Expected the receiver number, given: %+v", given)
+	}
+
+	reports := SmsReports(&smsapi.SmsResponse{Id: "1"})
+
+	if err := harness.Run(context.Background(), Delivered(), reports); !errors.Is(err, ErrCallbackType) {
+		t.Errorf("Expected ErrCallbackType for delivery reports, given: %v", err)
+	}
+
+	if err := harness.ReceiveMms(context.Background(), &webhook.InboundMms{From: "48500600700"}); !errors.Is(err, ErrCallbackType) {
+		t.Errorf("Expected ErrCallbackType for mms, given: %v", err)
+	}
+
+	dlr := NewHarness(&smsapi.Callback{Url: "/dlr", Type: "mms"}, nil)
+
+	if err := dlr.DeliverReports(context.Background(), reports...); !errors.Is(err, ErrCallbackType) {
+		t.Errorf("Expected ErrCallbackType for sms reports, given: %v", err)
+	}
+}
+
+func TestMfaReports(t *testing.T) {
+	var given []*webhook.DeliveryReport
+
+	handler := webhook.NewDeliveryReportHandler(webhook.ChannelSms, func(ctx context.Context, report *webhook.DeliveryReport) error {
+		given = append(given, report)
+
+		return nil
+	})
+
+	harness := NewHarness(&smsapi.Callback{Url: "/dlr", Type: "sms"}, handler)
+	harness.Wait = noWait
+
+	reports := MfaReports(&smsapi.MfaCode{Id: "1", Code: "123456", PhoneNumber: "48500600700", From: "Test"})
+
+	if err := harness.Run(context.Background(), Delivered(), reports); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(given) != 2 || given[1].MessageId != "1" || given[1].To != "48500600700" || given[1].From != "Test" || given[1].Status != smsapi.MessageStatusDelivered {
+		t.Errorf("Unexpected reports: %+v", given)
+	}
+}
+
+func TestHarnessRemoteAddr(t *testing.T) {
+	defer func(networks []netip.Prefix) { webhook.SmsapiNetworks = networks }(webhook.SmsapiNetworks)
+
+	webhook.SmsapiNetworks = webhook.MustParsePrefixes("198.51.100.0/24")
+
+	handler := webhook.NewDeliveryReportHandler(webhook.ChannelSms, func(ctx context.Context, report *webhook.DeliveryReport) error {
+		return nil
+	})
+
+	harness := NewHarness(&smsapi.Callback{Url: "/dlr", Type: "sms"}, (&webhook.Guard{}).Wrap(handler))
+
+	reports := SmsReports(&smsapi.SmsResponse{Id: "1"})
+
+	if err := harness.DeliverReports(context.Background(), reports...); err != nil {
+		t.Errorf("Expected callback from SmsapiNetworks to be accepted, given: %v", err)
+	}
+
+	harness.RemoteAddr = "203.0.113.1:1234"
+
+	if err := harness.DeliverReports(context.Background(), reports...); !errors.Is(err, ErrNotAcknowledged) {
+		t.Errorf("Expected callback from another network to be rejected, given: %v", err)
+	}
+}
+
+func TestDeliveryReportRequestComma(t *testing.T) {
+	reports := SmsReports(&smsapi.SmsResponse{Id: "1", Idx: "order-1,2"})
+
+	if _, err := DeliveryReportRequest("/dlr", reports...); !errors.Is(err, ErrCommaInValue) {
+		t.Errorf("Expected ErrCommaInValue, given: %v", err)
+	}
+}